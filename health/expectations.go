@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// List of errors
+var (
+	errorIndexDoesNotExist      = errors.New("index not found in collection")
+	errorIndexDoesNotMatch      = errors.New("index does not match the expected definition in collection")
+	errorCollectionNotCapped    = errors.New("collection is not capped")
+	errorCollectionNoValidator  = errors.New("collection has no validator")
+	errorCollectionOptionsCheck = errors.New("unable to get the indexes and options of collection")
+)
+
+// CollectionExpectation declares the indexes and options a collection is expected to have
+type CollectionExpectation struct {
+	// Indexes are the indexes that must exist, identified by name (or by the default name derived from the keys),
+	// with the same keys, uniqueness, sparseness, TTL, partial filter and collation
+	Indexes []mongoDriver.IndexSpec
+	// Capped is whether the collection must be capped
+	Capped bool
+	// Validator is whether the collection must have a validator
+	Validator bool
+}
+
+// WithCollectionExpectations enables a check that the given collections have the expected indexes and options.
+// A missing or mismatched index, or a missing collection option, reports a CRITICAL status naming the index or
+// option and the collection
+func WithCollectionExpectations(expectations map[Database]map[Collection]CollectionExpectation) Option {
+	return func(c *client) {
+		c.collectionExpectations = expectations
+	}
+}
+
+// checkCollectionExpectations checks each collection has the expected indexes and options, returning an error naming
+// the first missing index or option found. Databases and collections are checked in name order
+func (m *client) checkCollectionExpectations(ctx context.Context) error {
+	databases := make([]string, 0, len(m.collectionExpectations))
+	for database := range m.collectionExpectations {
+		databases = append(databases, string(database))
+	}
+	sort.Strings(databases)
+
+	for _, database := range databases {
+		expectations := m.collectionExpectations[Database(database)]
+		collections := make([]string, 0, len(expectations))
+		for collection := range expectations {
+			collections = append(collections, string(collection))
+		}
+		sort.Strings(collections)
+
+		for _, collection := range collections {
+			if err := m.checkCollectionExpectation(ctx, database, collection, expectations[Collection(collection)]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *client) checkCollectionExpectation(ctx context.Context, database, collection string, expectation CollectionExpectation) error {
+	logData := log.Data{"Database": database, "Collection": collection}
+	namespace := database + "." + collection
+
+	if len(expectation.Indexes) > 0 {
+		indexes, err := m.mongoConnection.CollectionFor(database, collection).ListIndexes(ctx)
+		if err != nil {
+			log.Error(ctx, "Failed to list the indexes of the collection", err, logData)
+			return fmt.Errorf("%w: %s", errorCollectionOptionsCheck, namespace)
+		}
+
+		if err = checkIndexes(indexes, expectation.Indexes, namespace); err != nil {
+			log.Error(ctx, "Expected index does not exist in the collection", err, logData)
+			return err
+		}
+	}
+
+	if expectation.Capped || expectation.Validator {
+		opts, err := m.mongoConnection.CollectionOptionsFor(ctx, database, collection)
+		if errors.Is(err, mongoDriver.ErrNoDocumentFound) {
+			log.Error(ctx, "Collection does not exist in the database", errorCollectionDoesNotExist, logData)
+			return fmt.Errorf("%w: %s", errorCollectionDoesNotExist, namespace)
+		}
+		if err != nil {
+			log.Error(ctx, "Failed to get the options of the collection", err, logData)
+			return fmt.Errorf("%w: %s", errorCollectionOptionsCheck, namespace)
+		}
+
+		if err = checkCollectionOptions(opts, expectation, namespace); err != nil {
+			log.Error(ctx, "Expected option is not set on the collection", err, logData)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkIndexes returns an error naming the first expected index that is missing from, or does not match, the
+// existing indexes of the collection
+func checkIndexes(existing, expected []mongoDriver.IndexSpec, namespace string) error {
+	byName := make(map[string]mongoDriver.IndexSpec, len(existing))
+	for _, spec := range existing {
+		byName[spec.Name] = spec
+	}
+
+	for _, spec := range expected {
+		name := spec.IndexName()
+		e, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w %s: %s", errorIndexDoesNotExist, namespace, name)
+		}
+		if !e.Matches(spec) {
+			return fmt.Errorf("%w %s: %s", errorIndexDoesNotMatch, namespace, name)
+		}
+	}
+
+	return nil
+}
+
+// checkCollectionOptions returns an error naming the first expected option not set on the collection
+func checkCollectionOptions(opts *mongoDriver.CollectionOptions, expectation CollectionExpectation, namespace string) error {
+	switch {
+	case expectation.Capped && !opts.Capped:
+		return fmt.Errorf("%w: %s", errorCollectionNotCapped, namespace)
+	case expectation.Validator && len(opts.Validator) == 0:
+		return fmt.Errorf("%w: %s", errorCollectionNoValidator, namespace)
+	}
+
+	return nil
+}
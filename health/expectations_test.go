@@ -0,0 +1,81 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCheckIndexes(t *testing.T) {
+	ttl := time.Hour
+	existing := []mongoDriver.IndexSpec{
+		{Name: "_id_", Keys: bson.D{{Key: "_id", Value: int32(1)}}},
+		{Name: "state_1", Keys: bson.D{{Key: "state", Value: int32(1)}}},
+		{Name: "expiry", Keys: bson.D{{Key: "created", Value: int32(1)}}, ExpireAfter: &ttl},
+	}
+
+	Convey("Given the expected indexes all exist with the same definitions", t, func() {
+		expected := []mongoDriver.IndexSpec{
+			{Keys: bson.D{{Key: "state", Value: 1}}},
+			{Name: "expiry", Keys: bson.D{{Key: "created", Value: 1}}, ExpireAfter: &ttl},
+		}
+
+		Convey("Then no error is returned", func() {
+			So(checkIndexes(existing, expected, "db.coll"), ShouldBeNil)
+		})
+	})
+
+	Convey("Given an expected index does not exist", t, func() {
+		expected := []mongoDriver.IndexSpec{{Keys: bson.D{{Key: "name", Value: 1}}}}
+
+		Convey("Then the missing index and the collection are reported", func() {
+			err := checkIndexes(existing, expected, "db.coll")
+			So(errors.Is(err, errorIndexDoesNotExist), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "index not found in collection db.coll: name_1")
+		})
+	})
+
+	Convey("Given an expected index exists with a different definition", t, func() {
+		expected := []mongoDriver.IndexSpec{{Keys: bson.D{{Key: "state", Value: 1}}, Unique: true}}
+
+		Convey("Then the mismatched index and the collection are reported", func() {
+			err := checkIndexes(existing, expected, "db.coll")
+			So(errors.Is(err, errorIndexDoesNotMatch), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "index does not match the expected definition in collection db.coll: state_1")
+		})
+	})
+}
+
+func TestCheckCollectionOptions(t *testing.T) {
+	Convey("Given a capped collection with a validator", t, func() {
+		opts := &mongoDriver.CollectionOptions{Capped: true, Size: 1024, Validator: bson.Raw{5, 0, 0, 0, 0}}
+
+		Convey("Then the expected options are satisfied", func() {
+			So(checkCollectionOptions(opts, CollectionExpectation{Capped: true, Validator: true}, "db.coll"), ShouldBeNil)
+		})
+	})
+
+	Convey("Given an uncapped collection without a validator", t, func() {
+		opts := &mongoDriver.CollectionOptions{}
+
+		Convey("Then an expected capped option is reported", func() {
+			err := checkCollectionOptions(opts, CollectionExpectation{Capped: true}, "db.coll")
+			So(errors.Is(err, errorCollectionNotCapped), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "collection is not capped: db.coll")
+		})
+
+		Convey("Then an expected validator is reported", func() {
+			err := checkCollectionOptions(opts, CollectionExpectation{Validator: true}, "db.coll")
+			So(errors.Is(err, errorCollectionNoValidator), ShouldBeTrue)
+		})
+
+		Convey("Then no error is returned when no options are expected", func() {
+			So(checkCollectionOptions(opts, CollectionExpectation{}, "db.coll"), ShouldBeNil)
+		})
+	})
+}
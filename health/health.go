@@ -3,6 +3,10 @@ package health
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
 
 	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
 
@@ -17,6 +21,13 @@ var (
 	healthyMessage = "mongodb is OK"
 	// healthyCollectionsMessage is the message that will be appended in healthcheck when all the collections exist
 	healthyCollectionsMessage = " and all expected collections exist"
+	// healthyExpectationsMessage is the message that will be appended in healthcheck when all the expected indexes and
+	// collection options exist
+	healthyExpectationsMessage = " and all expected indexes and collection options exist"
+	// healthyReplicaSetMessage is the message that will be appended in healthcheck when the replica set is healthy
+	healthyReplicaSetMessage = " and the replica set is healthy"
+	// slowMessage is the message that will be used in healthcheck when mongo is reachable but slow
+	slowMessage = "mongodb is slow: ping took %s, exceeding the warning threshold of %s"
 )
 
 // List of errors
@@ -28,8 +39,11 @@ var (
 // CheckMongoClient provides a healthcheck.Client implementation for health checking the service
 type CheckMongoClient struct {
 	client
-	healthcheck      func(context.Context) error
-	checkCollections func(context.Context) error
+	healthcheck         func(context.Context) error
+	checkCollections    func(context.Context) error
+	checkReplicaSet     func(context.Context) error
+	checkExpectations   func(context.Context) error
+	consecutiveFailures atomic.Int32
 }
 
 type (
@@ -39,27 +53,80 @@ type (
 	Collection string
 )
 
+// pinger is the part of a MongoConnection used to ping the server
+type pinger interface {
+	Ping(ctx context.Context, timeoutInSeconds time.Duration) error
+}
+
 // client provides a healthcheck.Client implementation for health checking the service
 type client struct {
 	mongoConnection    *mongoDriver.MongoConnection
+	pinger             pinger
+	now                func() time.Time
 	databaseCollection map[Database][]Collection
+	replicaSetCheck    bool
+	replicaSetMaxLag   time.Duration
+	warnLatency        time.Duration
+	criticalLatency    time.Duration
+	failureThreshold   int32
+
+	collectionExpectations map[Database]map[Collection]CollectionExpectation
+}
+
+// Option configures the optional checks made by a health check client
+type Option func(*client)
+
+// WithReplicaSetCheck enables a check of the replica set member states. The check reports a WARNING status when the
+// replica set has no primary, when a member is RECOVERING, DOWN or in ROLLBACK, or when the replication lag of a
+// secondary exceeds maxLag (a maxLag <= 0 disables the lag check)
+func WithReplicaSetCheck(maxLag time.Duration) Option {
+	return func(c *client) {
+		c.replicaSetCheck = true
+		c.replicaSetMaxLag = maxLag
+	}
+}
+
+// WithLatencyThresholds sets the ping latency thresholds. A ping that takes at least warn (if > 0) reports a WARNING
+// status, with the measured round-trip time. A ping that takes longer than critical (if > 0) is timed out and treated
+// as a failure, in place of the default timeout of 5 seconds
+func WithLatencyThresholds(warn, critical time.Duration) Option {
+	return func(c *client) {
+		c.warnLatency = warn
+		c.criticalLatency = critical
+	}
+}
+
+// WithFailureThreshold sets the number of consecutive ping failures before a CRITICAL status is reported. Failures
+// before the threshold is reached report a WARNING status. By default the first failure reports a CRITICAL status
+func WithFailureThreshold(consecutiveFailures int) Option {
+	return func(c *client) {
+		c.failureThreshold = int32(consecutiveFailures)
+	}
 }
 
 // NewClient returns a new health check client using the given service
-func NewClient(mongoConnection *mongoDriver.MongoConnection) *CheckMongoClient {
-	return NewClientWithCollections(mongoConnection, nil)
+func NewClient(mongoConnection *mongoDriver.MongoConnection, opts ...Option) *CheckMongoClient {
+	return NewClientWithCollections(mongoConnection, nil, opts...)
 }
 
 // NewClientWithCollections returns a new health check client containing the collections using the given service
-func NewClientWithCollections(mongoConnection *mongoDriver.MongoConnection, clientDatabaseCollection map[Database][]Collection) *CheckMongoClient {
+func NewClientWithCollections(mongoConnection *mongoDriver.MongoConnection, clientDatabaseCollection map[Database][]Collection, opts ...Option) *CheckMongoClient {
 	c := client{
 		mongoConnection:    mongoConnection,
+		pinger:             mongoConnection,
+		now:                time.Now,
 		databaseCollection: clientDatabaseCollection,
+		failureThreshold:   1,
+	}
+	for _, o := range opts {
+		o(&c)
 	}
 	return &CheckMongoClient{
-		client:           c,
-		healthcheck:      c.healthcheck,
-		checkCollections: c.checkCollections,
+		client:            c,
+		healthcheck:       c.healthcheck,
+		checkCollections:  c.checkCollections,
+		checkReplicaSet:   c.checkReplicaSet,
+		checkExpectations: c.checkCollectionExpectations,
 	}
 }
 
@@ -94,8 +161,18 @@ func find(slice []string, val string) bool {
 }
 
 // Healthcheck calls service to check its health status
+// The ping is timed out after the critical latency, if set, or otherwise after 5 seconds
 func (m *client) healthcheck(ctx context.Context) error {
-	err := m.mongoConnection.Ping(ctx, timeOutInSeconds)
+	pingTimeout := time.Duration(timeOutInSeconds)
+	if m.criticalLatency > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.criticalLatency)
+		defer cancel()
+		// Ping's timeout is in whole seconds, so is rounded up; the context times out the ping at the critical latency
+		pingTimeout = time.Duration(math.Ceil(m.criticalLatency.Seconds()))
+	}
+
+	err := m.pinger.Ping(ctx, pingTimeout)
 	if err != nil {
 		log.Error(ctx, "Ping mongo", err)
 		return err
@@ -106,12 +183,24 @@ func (m *client) healthcheck(ctx context.Context) error {
 
 // Checker calls an api health endpoint and  updates the provided CheckState accordingly
 func (c *CheckMongoClient) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	start := c.now()
 	err := c.healthcheck(ctx)
+	latency := c.now().Sub(start)
 	if err != nil {
+		failures := c.consecutiveFailures.Add(1)
+		if failures < c.failureThreshold {
+			state.Update(healthcheck.StatusWarning, fmt.Sprintf("%s (consecutive failure %d of %d before critical)", err.Error(), failures, c.failureThreshold), 0)
+			return nil
+		}
 		state.Update(healthcheck.StatusCritical, err.Error(), 0)
 		return nil
 	}
-	msg := healthyMessage
+	c.consecutiveFailures.Store(0)
+
+	status, msg := healthcheck.StatusOK, healthyMessage
+	if c.warnLatency > 0 && latency >= c.warnLatency {
+		status, msg = healthcheck.StatusWarning, fmt.Sprintf(slowMessage, latency, c.warnLatency)
+	}
 
 	if c.databaseCollection != nil && len(c.databaseCollection) > 0 {
 		err = c.checkCollections(ctx)
@@ -123,6 +212,26 @@ func (c *CheckMongoClient) Checker(ctx context.Context, state *healthcheck.Check
 		msg += healthyCollectionsMessage
 	}
 
-	state.Update(healthcheck.StatusOK, msg, 0)
+	if len(c.collectionExpectations) > 0 {
+		err = c.checkExpectations(ctx)
+		if err != nil {
+			log.Error(ctx, "Error checking collection indexes and options in mongo", err)
+			state.Update(healthcheck.StatusCritical, err.Error(), 0)
+			return nil
+		}
+		msg += healthyExpectationsMessage
+	}
+
+	if c.replicaSetCheck {
+		err = c.checkReplicaSet(ctx)
+		if err != nil {
+			log.Warn(ctx, "Replica set in mongo is degraded", log.FormatErrors([]error{err}))
+			state.Update(healthcheck.StatusWarning, err.Error(), 0)
+			return nil
+		}
+		msg += healthyReplicaSetMessage
+	}
+
+	state.Update(status, msg, 0)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	. "github.com/smartystreets/goconvey/convey"
@@ -114,6 +115,197 @@ func TestClient_GetOutput(t *testing.T) {
 	})
 }
 
+func TestClient_ReplicaSetCheck(t *testing.T) {
+
+	ctx := context.Background()
+
+	Convey("Given a CheckMongoClient with the replica set check enabled", t, func() {
+		// CheckState for test validation
+		checkState := healthcheck.NewCheckState("test-mongodb")
+		c := NewClient(nil, WithReplicaSetCheck(10*time.Second))
+
+		Convey("When the health endpoint is successful", func() {
+			c.healthcheck = healthSuccess
+
+			Convey("And the replica set is healthy", func() {
+				c.checkReplicaSet = func(context.Context) error {
+					return nil
+				}
+				Convey("Then Checker updates the CheckState to an OK status", func() {
+					c.Checker(ctx, checkState)
+					So(checkState.Status(), ShouldEqual, healthcheck.StatusOK)
+					So(checkState.Message(), ShouldEqual, "mongodb is OK and the replica set is healthy")
+				})
+			})
+
+			Convey("And the replica set is degraded", func() {
+				errDegraded := errors.New("replica set is degraded: no primary member")
+				c.checkReplicaSet = func(context.Context) error {
+					return errDegraded
+				}
+				Convey("Then Checker updates the CheckState to a WARNING status", func() {
+					c.Checker(ctx, checkState)
+					So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+					So(checkState.Message(), ShouldEqual, errDegraded.Error())
+				})
+			})
+		})
+
+		Convey("When the health endpoint returns an error", func() {
+			c.healthcheck = healthFailure
+			c.checkReplicaSet = func(context.Context) error {
+				return nil
+			}
+			Convey("Then Checker updates the CheckState to a CRITICAL status", func() {
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusCritical)
+				So(checkState.Message(), ShouldEqual, errUnableToConnect.Error())
+			})
+		})
+	})
+}
+
+func TestClient_CollectionExpectations(t *testing.T) {
+
+	ctx := context.Background()
+
+	Convey("Given a CheckMongoClient with collection expectations", t, func() {
+		// CheckState for test validation
+		checkState := healthcheck.NewCheckState("test-mongodb")
+		c := NewClient(nil, WithCollectionExpectations(map[Database]map[Collection]CollectionExpectation{
+			"databaseOne": {"collectionOne": {Capped: true}},
+		}))
+
+		Convey("When the health endpoint is successful", func() {
+			c.healthcheck = healthSuccess
+
+			Convey("And the expected indexes and options exist", func() {
+				c.checkExpectations = func(context.Context) error {
+					return nil
+				}
+				Convey("Then Checker updates the CheckState to an OK status", func() {
+					c.Checker(ctx, checkState)
+					So(checkState.Status(), ShouldEqual, healthcheck.StatusOK)
+					So(checkState.Message(), ShouldEqual, "mongodb is OK and all expected indexes and collection options exist")
+				})
+			})
+
+			Convey("And an expected index does not exist", func() {
+				errMissing := errors.New("index not found in collection databaseOne.collectionOne: state_1")
+				c.checkExpectations = func(context.Context) error {
+					return errMissing
+				}
+				Convey("Then Checker updates the CheckState to a CRITICAL status naming the index", func() {
+					c.Checker(ctx, checkState)
+					So(checkState.Status(), ShouldEqual, healthcheck.StatusCritical)
+					So(checkState.Message(), ShouldEqual, errMissing.Error())
+				})
+			})
+		})
+	})
+}
+
+func TestClient_LatencyAndFailureThresholds(t *testing.T) {
+
+	ctx := context.Background()
+
+	Convey("Given a CheckMongoClient with latency thresholds", t, func() {
+		// CheckState for test validation
+		checkState := healthcheck.NewCheckState("test-mongodb")
+		c := NewClient(nil, WithLatencyThresholds(10*time.Millisecond, time.Second))
+
+		Convey("When the health endpoint responds within the warning threshold", func() {
+			c.healthcheck = healthSuccess
+			Convey("Then Checker updates the CheckState to an OK status", func() {
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusOK)
+				So(checkState.Message(), ShouldEqual, "mongodb is OK")
+			})
+		})
+
+		Convey("When the health endpoint responds slower than the warning threshold", func() {
+			c.healthcheck = func(context.Context) error {
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			}
+			Convey("Then Checker updates the CheckState to a WARNING status with the measured latency", func() {
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+				So(checkState.Message(), ShouldStartWith, "mongodb is slow: ping took ")
+				So(checkState.Message(), ShouldEndWith, ", exceeding the warning threshold of 10ms")
+			})
+		})
+	})
+
+	Convey("Given a CheckMongoClient with a critical latency above the default ping timeout of 5 seconds", t, func() {
+		// CheckState for test validation
+		checkState := healthcheck.NewCheckState("test-mongodb")
+		c := NewClient(nil, WithLatencyThresholds(2*time.Second, 10*time.Second))
+		clock := time.Now()
+		c.now = func() time.Time { return clock }
+
+		Convey("When a ping takes 6 seconds", func() {
+			ping := &slowPinger{took: 6 * time.Second, advance: func(d time.Duration) { clock = clock.Add(d) }}
+			c.pinger = ping
+			c.healthcheck = c.client.healthcheck
+
+			Convey("Then the ping is not timed out, and Checker updates the CheckState to a WARNING status", func() {
+				c.Checker(ctx, checkState)
+				So(ping.timeout, ShouldEqual, 10)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+				So(checkState.Message(), ShouldEqual, "mongodb is slow: ping took 6s, exceeding the warning threshold of 2s")
+			})
+		})
+	})
+
+	Convey("Given a CheckMongoClient without a critical latency", t, func() {
+		c := NewClient(nil)
+
+		Convey("When a ping is made, it is timed out after 5 seconds", func() {
+			ping := &slowPinger{advance: func(time.Duration) {}}
+			c.pinger = ping
+			So(c.client.healthcheck(ctx), ShouldBeNil)
+			So(ping.timeout, ShouldEqual, 5)
+		})
+	})
+
+	Convey("Given a CheckMongoClient with a failure threshold of 3", t, func() {
+		// CheckState for test validation
+		checkState := healthcheck.NewCheckState("test-mongodb")
+		c := NewClient(nil, WithFailureThreshold(3))
+		c.healthcheck = healthFailure
+
+		Convey("When the health endpoint returns an error fewer than 3 consecutive times", func() {
+			c.Checker(ctx, checkState)
+			So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+			So(checkState.Message(), ShouldEqual, "failed to connect (consecutive failure 1 of 3 before critical)")
+			c.Checker(ctx, checkState)
+
+			Convey("Then Checker updates the CheckState to a WARNING status", func() {
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+				So(checkState.Message(), ShouldEqual, "failed to connect (consecutive failure 2 of 3 before critical)")
+			})
+
+			Convey("And then 3 consecutive times, Checker updates the CheckState to a CRITICAL status", func() {
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusCritical)
+				So(checkState.Message(), ShouldEqual, errUnableToConnect.Error())
+			})
+
+			Convey("And then succeeds, the consecutive failures are reset", func() {
+				c.healthcheck = healthSuccess
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusOK)
+
+				c.healthcheck = healthFailure
+				c.Checker(ctx, checkState)
+				So(checkState.Status(), ShouldEqual, healthcheck.StatusWarning)
+				So(checkState.Message(), ShouldEqual, "failed to connect (consecutive failure 1 of 3 before critical)")
+			})
+		})
+	})
+}
+
 var (
 	healthSuccess = func(context.Context) error {
 		return nil
@@ -123,3 +315,22 @@ var (
 		return errUnableToConnect
 	}
 )
+
+// slowPinger simulates a ping that takes the given time, failing as a real ping would if it is timed out first
+type slowPinger struct {
+	took    time.Duration
+	advance func(time.Duration)
+	timeout time.Duration
+}
+
+func (p *slowPinger) Ping(ctx context.Context, timeoutInSeconds time.Duration) error {
+	p.timeout = timeoutInSeconds
+	if p.took > timeoutInSeconds*time.Second {
+		return context.DeadlineExceeded
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < p.took {
+		return context.DeadlineExceeded
+	}
+	p.advance(p.took)
+	return nil
+}
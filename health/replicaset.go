@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Replica set member states, as reported by replSetGetStatus
+const (
+	memberStatePrimary    = 1
+	memberStateSecondary  = 2
+	memberStateRecovering = 3
+	memberStateDown       = 8
+	memberStateRollback   = 9
+)
+
+var errorReplicaSetStatusUnavailable = errors.New("unable to get replica set status")
+
+// replicaSetStatus is the subset of the replSetGetStatus command response used to check the replica set
+type replicaSetStatus struct {
+	Set     string             `bson:"set"`
+	Members []replicaSetMember `bson:"members"`
+}
+
+type replicaSetMember struct {
+	Name       string    `bson:"name"`
+	State      int       `bson:"state"`
+	StateStr   string    `bson:"stateStr"`
+	OptimeDate time.Time `bson:"optimeDate"`
+}
+
+// helloResponse is the subset of the hello command response used to check the replica set when replSetGetStatus is
+// not available (e.g. on DocumentDB)
+type helloResponse struct {
+	SetName string `bson:"setName"`
+	Primary string `bson:"primary"`
+}
+
+// checkReplicaSet checks the state of the replica set members, returning an error describing any issues found
+// A server that is not a member of a replica set has no issues
+func (m *client) checkReplicaSet(ctx context.Context) error {
+	var status replicaSetStatus
+	err := m.mongoConnection.RunAdminCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}, &status)
+	if err == nil {
+		return issuesError(status.issues(m.replicaSetMaxLag))
+	}
+
+	var hello helloResponse
+	if err = m.mongoConnection.RunAdminCommand(ctx, bson.D{{Key: "hello", Value: 1}}, &hello); err != nil {
+		log.Error(ctx, "Failed to get the replica set status", err)
+		return errorReplicaSetStatusUnavailable
+	}
+
+	if hello.SetName != "" && hello.Primary == "" {
+		return issuesError([]string{"no primary member"})
+	}
+
+	return nil
+}
+
+// issues returns a description of each issue with the replica set: a missing primary, members that are recovering,
+// down or rolling back, and secondaries whose replication lag behind the primary exceeds maxLag (if maxLag > 0)
+func (s replicaSetStatus) issues(maxLag time.Duration) []string {
+	var issues []string
+
+	var primary *replicaSetMember
+	for i, member := range s.Members {
+		if member.State == memberStatePrimary {
+			primary = &s.Members[i]
+		}
+	}
+	if primary == nil {
+		issues = append(issues, "no primary member")
+	}
+
+	for _, member := range s.Members {
+		switch member.State {
+		case memberStateRecovering, memberStateDown, memberStateRollback:
+			issues = append(issues, fmt.Sprintf("member %s is %s", member.Name, member.StateStr))
+		case memberStateSecondary:
+			if primary == nil || maxLag <= 0 {
+				continue
+			}
+			if lag := primary.OptimeDate.Sub(member.OptimeDate); lag > maxLag {
+				issues = append(issues, fmt.Sprintf("member %s replication lag %s exceeds %s", member.Name, lag, maxLag))
+			}
+		}
+	}
+
+	return issues
+}
+
+func issuesError(issues []string) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return errors.New("replica set is degraded: " + strings.Join(issues, "; "))
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReplicaSetStatus_Issues(t *testing.T) {
+	now := time.Now()
+
+	Convey("Given a replica set with a primary and up to date secondaries", t, func() {
+		status := replicaSetStatus{Members: []replicaSetMember{
+			{Name: "a", State: memberStatePrimary, StateStr: "PRIMARY", OptimeDate: now},
+			{Name: "b", State: memberStateSecondary, StateStr: "SECONDARY", OptimeDate: now.Add(-time.Second)},
+		}}
+
+		Convey("Then there are no issues", func() {
+			So(status.issues(10*time.Second), ShouldBeEmpty)
+			So(issuesError(status.issues(10*time.Second)), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a replica set without a primary", t, func() {
+		status := replicaSetStatus{Members: []replicaSetMember{
+			{Name: "a", State: memberStateSecondary, StateStr: "SECONDARY", OptimeDate: now},
+			{Name: "b", State: memberStateSecondary, StateStr: "SECONDARY", OptimeDate: now},
+		}}
+
+		Convey("Then the missing primary is reported", func() {
+			So(status.issues(10*time.Second), ShouldResemble, []string{"no primary member"})
+		})
+	})
+
+	Convey("Given a replica set with a recovering member and a lagging secondary", t, func() {
+		status := replicaSetStatus{Members: []replicaSetMember{
+			{Name: "a", State: memberStatePrimary, StateStr: "PRIMARY", OptimeDate: now},
+			{Name: "b", State: memberStateRecovering, StateStr: "RECOVERING", OptimeDate: now},
+			{Name: "c", State: memberStateSecondary, StateStr: "SECONDARY", OptimeDate: now.Add(-time.Minute)},
+		}}
+
+		Convey("Then both issues are reported", func() {
+			issues := status.issues(10 * time.Second)
+			So(issues, ShouldResemble, []string{"member b is RECOVERING", "member c replication lag 1m0s exceeds 10s"})
+			So(issuesError(issues).Error(), ShouldEqual, "replica set is degraded: member b is RECOVERING; member c replication lag 1m0s exceeds 10s")
+		})
+
+		Convey("Then the lag is not reported when the lag check is disabled", func() {
+			So(status.issues(0), ShouldResemble, []string{"member b is RECOVERING"})
+		})
+	})
+}
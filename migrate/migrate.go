@@ -0,0 +1,330 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ONSdigital/dp-mongodb/v3/dplock"
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Collection is the name of the collection in which applied migrations are recorded
+const Collection = "schema_migrations"
+
+// lockResourceID is the id of the resource locked while migrations are run
+const lockResourceID = "migrate"
+
+// LockRetryPeriod is the time period between attempts to acquire the migration lock, once the lock's own retries
+// have been exhausted because another instance is running migrations
+var LockRetryPeriod = time.Second
+
+// ErrInvalidVersion is an error returned when a migration has a version that is not positive
+var ErrInvalidVersion = errors.New("migration version must be positive")
+
+// ErrDuplicateVersion is an error returned when more than one migration is registered with the same version
+var ErrDuplicateVersion = errors.New("duplicate migration version")
+
+// ErrNoUpMigration is an error returned when a migration is registered without an Up function
+var ErrNoUpMigration = errors.New("migration has no up function")
+
+// ErrNoDownMigration is an error returned when a migration to be rolled back has no Down function
+var ErrNoDownMigration = errors.New("migration has no down function")
+
+// ErrUnknownMigration is an error returned when a migration to be rolled back is recorded as applied, but is not registered
+var ErrUnknownMigration = errors.New("applied migration is not registered")
+
+// ErrMigrationRunning is an error returned when a migration to be run has been claimed by another run, e.g. by another
+// instance that took the migration lock after it expired. If the run that claimed the migration was interrupted, its
+// record (with state "running") must be removed from the schema_migrations collection before the migration can be run
+var ErrMigrationRunning = errors.New("migration has been claimed by another run")
+
+// Func is the type signature of a function that migrates the database, in either direction
+type Func func(ctx context.Context, conn *mongoDriver.MongoConnection) error
+
+// Migration is a single versioned change to the database
+type Migration struct {
+	Version     int
+	Description string
+	Up          Func
+	Down        Func
+}
+
+// State is the state of a recorded migration
+type State string
+
+const (
+	Running State = "running" // The migration has been claimed by a run, and is being applied
+	Applied State = "applied" // The migration has been applied
+)
+
+// AppliedMigration is the record of a migration that has been applied to the database, or is being applied
+// Records without a state, made by earlier versions of this package, are of applied migrations
+type AppliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	State       State     `bson:"state,omitempty"`
+	StartedAt   time.Time `bson:"started_at,omitempty"`
+	AppliedAt   time.Time `bson:"applied_at,omitempty"`
+}
+
+// Locker defines the dplock.Lock methods used to ensure only one instance runs migrations at a time
+type Locker interface {
+	Acquire(ctx context.Context, id string) (lockID string, err error)
+	Unlock(ctx context.Context, lockID string)
+	Close(ctx context.Context)
+}
+
+// Migrator runs the registered migrations against a database, recording the applied migrations in the
+// schema_migrations collection
+type Migrator struct {
+	Conn       *mongoDriver.MongoConnection
+	Lock       Locker
+	Migrations []Migration
+}
+
+type RunOption func(*runOptions)
+
+var (
+	// DryRun reports the migrations that would be run, without running them or recording them as run
+	DryRun = func() RunOption { return func(r *runOptions) { r.dryRun = true } }
+)
+
+type runOptions struct {
+	dryRun bool
+}
+
+func newRunOptions(opts ...RunOption) *runOptions {
+	r := &runOptions{}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// New creates a new migrator for the provided connection and migrations, with a dplock.Lock to coordinate instances
+// The migrations may be given in any order; they are run in order of version
+func New(ctx context.Context, mongoConnection *mongoDriver.MongoConnection, migrations ...Migration) (*Migrator, error) {
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		Conn:       mongoConnection,
+		Lock:       dplock.New(ctx, mongoConnection, Collection),
+		Migrations: sorted,
+	}, nil
+}
+
+func sortMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		switch {
+		case m.Version <= 0:
+			return nil, fmt.Errorf("%w: %d", ErrInvalidVersion, m.Version)
+		case m.Up == nil:
+			return nil, fmt.Errorf("%w: %d", ErrNoUpMigration, m.Version)
+		case i > 0 && sorted[i-1].Version == m.Version:
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, m.Version)
+		}
+	}
+
+	return sorted, nil
+}
+
+// Applied returns the migrations that have been applied to the database, in order of version, excluding those being
+// applied
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	var applied []AppliedMigration
+	filter := bson.M{"state": bson.M{"$ne": Running}}
+	if _, err := m.Conn.Collection(Collection).Find(ctx, filter, &applied, mongoDriver.Sort(bson.M{"_id": 1})); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Pending returns the registered migrations that have not been applied to the database, in order of version
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	isApplied := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		isApplied[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, mig := range m.Migrations {
+		if !isApplied[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up runs the pending migrations in order of version, under the migration lock, recording each as applied once it
+// has completed. It stops at the first migration that fails, returning the migrations successfully run (or, for a
+// DryRun, the migrations that would be run)
+// Before it is run, each migration is claimed by inserting its record with the state "running", so that it cannot be
+// run by two instances at the same time even if a migration outlasts the lock's TTL of dplock.TTL seconds and another
+// instance takes the lock; the other instance then stops with ErrMigrationRunning
+func (m *Migrator) Up(ctx context.Context, opts ...RunOption) ([]Migration, error) {
+	ro := newRunOptions(opts...)
+
+	lockID, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Lock.Unlock(ctx, lockID)
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ro.dryRun {
+		for _, mig := range pending {
+			log.Info(ctx, "dry run: migration would be applied", logData(mig))
+		}
+		return pending, nil
+	}
+
+	var run []Migration
+	for _, mig := range pending {
+		if err = m.claim(ctx, mig); err != nil {
+			return run, err
+		}
+
+		log.Info(ctx, "applying migration", logData(mig))
+		if err = mig.Up(ctx, m.Conn); err != nil {
+			log.Error(ctx, "migration failed", err, logData(mig))
+			if _, derr := m.Conn.Collection(Collection).DeleteOne(ctx, bson.M{"_id": mig.Version, "state": Running}); derr != nil {
+				log.Error(ctx, "failed to release claim of failed migration", derr, logData(mig))
+			}
+			return run, fmt.Errorf("migration %d failed: %w", mig.Version, err)
+		}
+
+		update := bson.M{"$set": bson.M{"state": Applied, "applied_at": time.Now().UTC()}}
+		if _, err = m.Conn.Collection(Collection).UpdateOne(ctx, bson.M{"_id": mig.Version, "state": Running}, update); err != nil {
+			return run, fmt.Errorf("failed to record migration %d as applied: %w", mig.Version, err)
+		}
+		run = append(run, mig)
+	}
+
+	return run, nil
+}
+
+// Down rolls back the applied migrations with a version greater than the target version, in reverse order of
+// version, under the migration lock, removing the record of each once it has been rolled back. All the migrations to
+// be rolled back must be registered with a Down function, otherwise nothing is rolled back. It stops at the first
+// migration that fails, returning the migrations successfully rolled back (or, for a DryRun, the migrations that would
+// be rolled back)
+func (m *Migrator) Down(ctx context.Context, targetVersion int, opts ...RunOption) ([]Migration, error) {
+	ro := newRunOptions(opts...)
+
+	lockID, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Lock.Unlock(ctx, lockID)
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[int]Migration, len(m.Migrations))
+	for _, mig := range m.Migrations {
+		registered[mig.Version] = mig
+	}
+
+	var toRollBack []Migration
+	for i := len(applied) - 1; i >= 0 && applied[i].Version > targetVersion; i-- {
+		mig, ok := registered[applied[i].Version]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: %d", ErrUnknownMigration, applied[i].Version)
+		case mig.Down == nil:
+			return nil, fmt.Errorf("%w: %d", ErrNoDownMigration, mig.Version)
+		}
+		toRollBack = append(toRollBack, mig)
+	}
+
+	if ro.dryRun {
+		for _, mig := range toRollBack {
+			log.Info(ctx, "dry run: migration would be rolled back", logData(mig))
+		}
+		return toRollBack, nil
+	}
+
+	var run []Migration
+	for _, mig := range toRollBack {
+		log.Info(ctx, "rolling back migration", logData(mig))
+		if err = mig.Down(ctx, m.Conn); err != nil {
+			log.Error(ctx, "migration roll back failed", err, logData(mig))
+			return run, fmt.Errorf("roll back of migration %d failed: %w", mig.Version, err)
+		}
+
+		if _, err = m.Conn.Collection(Collection).DeleteOne(ctx, bson.M{"_id": mig.Version}); err != nil {
+			return run, fmt.Errorf("failed to remove record of migration %d: %w", mig.Version, err)
+		}
+		run = append(run, mig)
+	}
+
+	return run, nil
+}
+
+// claim records the migration as running, returning ErrMigrationRunning if it has already been claimed by another run
+func (m *Migrator) claim(ctx context.Context, mig Migration) error {
+	record := AppliedMigration{Version: mig.Version, Description: mig.Description, State: Running, StartedAt: time.Now().UTC()}
+	_, err := m.Conn.Collection(Collection).InsertOne(ctx, record)
+	switch {
+	case mongoDriver.IsDuplicateKey(err):
+		return fmt.Errorf("%w: %d", ErrMigrationRunning, mig.Version)
+	case err != nil:
+		return fmt.Errorf("failed to claim migration %d: %w", mig.Version, err)
+	}
+
+	return nil
+}
+
+// Close closes the migration lock
+func (m *Migrator) Close(ctx context.Context) {
+	m.Lock.Close(ctx)
+}
+
+// acquire acquires the migration lock, waiting while another instance holds it until the context is done
+func (m *Migrator) acquire(ctx context.Context) (string, error) {
+	for {
+		lockID, err := m.Lock.Acquire(ctx, lockResourceID)
+		if !errors.Is(err, dplock.ErrAcquireMaxRetries) {
+			return lockID, err
+		}
+
+		log.Info(ctx, "waiting for another instance to complete migrations")
+		delay := time.NewTimer(LockRetryPeriod)
+		select {
+		case <-delay.C:
+		case <-ctx.Done():
+			delay.Stop()
+			return "", ctx.Err()
+		}
+	}
+}
+
+func logData(mig Migration) log.Data {
+	return log.Data{"version": mig.Version, "description": mig.Description}
+}
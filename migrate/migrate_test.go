@@ -0,0 +1,214 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-mongodb/v3/migrate"
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	. "github.com/smartystreets/goconvey/convey"
+	testMongoContainer "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	ctx       = context.Background()
+	errFailed = errors.New("migration failed")
+)
+
+// testLocker is a Locker that records the locks acquired and released
+type testLocker struct {
+	acquired, unlocked int
+}
+
+func (l *testLocker) Acquire(context.Context, string) (string, error) {
+	l.acquired++
+	return fmt.Sprintf("lock-%d", l.acquired), nil
+}
+
+func (l *testLocker) Unlock(context.Context, string) {
+	l.unlocked++
+}
+
+func (l *testLocker) Close(context.Context) {}
+
+func setState(state string) migrate.Func {
+	return func(ctx context.Context, conn *mongoDriver.MongoConnection) error {
+		_, err := conn.Collection("things").UpsertOne(ctx, bson.M{"_id": 1}, bson.M{"$set": bson.M{"state": state}})
+		return err
+	}
+}
+
+func TestNew(t *testing.T) {
+	Convey("New returns an error for a migration with a version that is not positive", t, func() {
+		_, err := migrate.New(ctx, nil, migrate.Migration{Version: 0, Up: setState("first")})
+		So(errors.Is(err, migrate.ErrInvalidVersion), ShouldBeTrue)
+	})
+
+	Convey("New returns an error for a migration without an up function", t, func() {
+		_, err := migrate.New(ctx, nil, migrate.Migration{Version: 1})
+		So(errors.Is(err, migrate.ErrNoUpMigration), ShouldBeTrue)
+	})
+
+	Convey("New returns an error for migrations with the same version", t, func() {
+		_, err := migrate.New(ctx, nil, migrate.Migration{Version: 1, Up: setState("first")}, migrate.Migration{Version: 1, Up: setState("second")})
+		So(errors.Is(err, migrate.ErrDuplicateVersion), ShouldBeTrue)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	Convey("Given a connection to a real mongodb server and a migrator with registered migrations", t, func() {
+		conn, cleanup := setupMongoConnection(t)
+		defer cleanup()
+
+		locker := &testLocker{}
+		m := &migrate.Migrator{
+			Conn: conn,
+			Lock: locker,
+			Migrations: []migrate.Migration{
+				{Version: 1, Description: "first", Up: setState("first"), Down: setState("")},
+				{Version: 2, Description: "second", Up: setState("second"), Down: setState("first")},
+			},
+		}
+
+		Convey("When a dry run of the migrations is made", func() {
+			run, err := m.Up(ctx, migrate.DryRun())
+
+			Convey("Then the pending migrations are reported, but not applied", func() {
+				So(err, ShouldBeNil)
+				So(run, ShouldHaveLength, 2)
+
+				applied, err := m.Applied(ctx)
+				So(err, ShouldBeNil)
+				So(applied, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the migrations are run", func() {
+			run, err := m.Up(ctx)
+
+			Convey("Then all the migrations are applied in order, and recorded, under the lock", func() {
+				So(err, ShouldBeNil)
+				So(run, ShouldHaveLength, 2)
+				So(locker.acquired, ShouldEqual, 1)
+				So(locker.unlocked, ShouldEqual, 1)
+
+				var thing struct {
+					State string `bson:"state"`
+				}
+				So(conn.Collection("things").FindOne(ctx, bson.M{"_id": 1}, &thing), ShouldBeNil)
+				So(thing.State, ShouldEqual, "second")
+
+				applied, err := m.Applied(ctx)
+				So(err, ShouldBeNil)
+				So(applied, ShouldHaveLength, 2)
+				So(applied[0].Version, ShouldEqual, 1)
+				So(applied[1].Version, ShouldEqual, 2)
+
+				pending, err := m.Pending(ctx)
+				So(err, ShouldBeNil)
+				So(pending, ShouldBeEmpty)
+			})
+
+			Convey("Then the migrations after a target version can be rolled back", func() {
+				run, err := m.Down(ctx, 1)
+				So(err, ShouldBeNil)
+				So(run, ShouldHaveLength, 1)
+				So(run[0].Version, ShouldEqual, 2)
+
+				pending, err := m.Pending(ctx)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 1)
+				So(pending[0].Version, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the lock expires while a migration runs, and another instance takes it and runs the migrations", func() {
+			var (
+				runs          int
+				concurrent    []migrate.Migration
+				concurrentErr error
+			)
+			other := &migrate.Migrator{Conn: conn, Lock: &testLocker{}}
+			m.Migrations[0].Up = func(ctx context.Context, conn *mongoDriver.MongoConnection) error {
+				runs++
+				// the testLocker always grants the lock, as if the lock of this run had expired
+				concurrent, concurrentErr = other.Up(ctx)
+				return setState("first")(ctx, conn)
+			}
+			other.Migrations = m.Migrations
+			run, err := m.Up(ctx)
+
+			Convey("Then the other instance does not run the migration claimed by the first", func() {
+				So(errors.Is(concurrentErr, migrate.ErrMigrationRunning), ShouldBeTrue)
+				So(concurrent, ShouldBeEmpty)
+				So(runs, ShouldEqual, 1)
+
+				So(err, ShouldBeNil)
+				So(run, ShouldHaveLength, 2)
+				applied, err := m.Applied(ctx)
+				So(err, ShouldBeNil)
+				So(applied, ShouldHaveLength, 2)
+				So(applied[0].State, ShouldEqual, migrate.Applied)
+			})
+		})
+
+		Convey("When a migration fails", func() {
+			m.Migrations = append(m.Migrations, migrate.Migration{Version: 3, Up: func(context.Context, *mongoDriver.MongoConnection) error { return errFailed }})
+			run, err := m.Up(ctx)
+
+			Convey("Then the migrations before it are applied and the error returned", func() {
+				So(errors.Is(err, errFailed), ShouldBeTrue)
+				So(run, ShouldHaveLength, 2)
+
+				pending, err := m.Pending(ctx)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 1)
+			})
+
+			Convey("Then a migration without a down function cannot be rolled back", func() {
+				m.Migrations[2].Up = setState("third")
+				_, err := m.Up(ctx)
+				So(err, ShouldBeNil)
+
+				_, err = m.Down(ctx, 0)
+				So(errors.Is(err, migrate.ErrNoDownMigration), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func setupMongoConnection(t *testing.T) (*mongoDriver.MongoConnection, func()) {
+	mongoServer, err := testMongoContainer.Run(ctx, "mongo:5.0.2")
+	if err != nil {
+		t.Fatalf("failed to start mongo server: %v", err)
+	}
+
+	connectionString, err := mongoServer.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get mongo server connection string: %v", err)
+	}
+
+	connectionStringURL, err := url.Parse(connectionString)
+	if err != nil {
+		t.Fatalf("failed to parse mongo server connection string: %v", err)
+	}
+
+	conn, err := mongoDriver.Open(&mongoDriver.MongoDriverConfig{
+		ConnectTimeout:  10 * time.Second,
+		QueryTimeout:    10 * time.Second,
+		ClusterEndpoint: connectionStringURL.Host,
+		Database:        "test-db",
+	})
+	if err != nil {
+		t.Fatalf("couldn't open mongo: %v", err)
+	}
+
+	return conn, func() {
+		mongoServer.Terminate(ctx)
+	}
+}
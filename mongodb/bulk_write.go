@@ -0,0 +1,132 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkWrite builds a list of write operations to be executed against a collection in a single round-trip
+type BulkWrite struct {
+	collection *Collection
+	models     []mongo.WriteModel
+	ordered    bool
+}
+
+// CollectionBulkWriteResult is the result type returned from BulkWrite operations.
+type CollectionBulkWriteResult struct {
+	InsertedCount int                       // The number of documents inserted.
+	MatchedCount  int                       // The number of documents matched by filters in update and replace operations.
+	ModifiedCount int                       // The number of documents modified by update and replace operations.
+	DeletedCount  int                       // The number of documents deleted.
+	UpsertedCount int                       // The number of documents upserted by update and replace operations.
+	UpsertedIDs   map[int]interface{}       // A map of operation index to the _id of each upserted document.
+	WriteErrors   []BulkWriteOperationError // The errors for the individual operations that failed, if any.
+}
+
+// BulkWriteOperationError is the error for an individual operation of a bulk write, whose Index is that of the failed
+// operation, in the order the operations were added
+type BulkWriteOperationError = WriteError
+
+// BulkWrite returns a new, empty, bulk write for the collection
+// By default the operations are executed in order, stopping at the first failure; use Unordered to change this
+func (c *Collection) BulkWrite() *BulkWrite {
+	return &BulkWrite{collection: c, ordered: true}
+}
+
+// Ordered sets the operations to be executed in the order they were added, stopping at the first failure
+func (b *BulkWrite) Ordered() *BulkWrite {
+	b.ordered = true
+	return b
+}
+
+// Unordered sets the operations to be executed in any order, continuing after any failures
+func (b *BulkWrite) Unordered() *BulkWrite {
+	b.ordered = false
+	return b
+}
+
+// InsertOne adds an operation that inserts the given document
+func (b *BulkWrite) InsertOne(document interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewInsertOneModel().SetDocument(document))
+	return b
+}
+
+// UpdateOne adds an operation that modifies a single document located by the provided selector
+func (b *BulkWrite) UpdateOne(selector interface{}, update interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewUpdateOneModel().SetFilter(selector).SetUpdate(update))
+	return b
+}
+
+// UpsertOne adds an operation that creates or updates a single document located by the provided selector
+func (b *BulkWrite) UpsertOne(selector interface{}, update interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewUpdateOneModel().SetFilter(selector).SetUpdate(update).SetUpsert(true))
+	return b
+}
+
+// UpdateMany adds an operation that modifies all documents located by the provided selector
+func (b *BulkWrite) UpdateMany(selector interface{}, update interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewUpdateManyModel().SetFilter(selector).SetUpdate(update))
+	return b
+}
+
+// ReplaceOne adds an operation that replaces a single document located by the provided selector
+func (b *BulkWrite) ReplaceOne(selector interface{}, replacement interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewReplaceOneModel().SetFilter(selector).SetReplacement(replacement))
+	return b
+}
+
+// DeleteOne adds an operation that deletes a single document located by the provided selector
+func (b *BulkWrite) DeleteOne(selector interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewDeleteOneModel().SetFilter(selector))
+	return b
+}
+
+// DeleteMany adds an operation that deletes all documents located by the provided selector
+func (b *BulkWrite) DeleteMany(selector interface{}) *BulkWrite {
+	b.models = append(b.models, mongo.NewDeleteManyModel().SetFilter(selector))
+	return b
+}
+
+// Len returns the number of operations added to the bulk write
+func (b *BulkWrite) Len() int {
+	return len(b.models)
+}
+
+// Execute executes the operations in a single round-trip. At least one operation must have been added
+// If any operations fail, the result (which reports the failures in its WriteErrors field, along with the counts for
+// the operations that succeeded) is returned together with the error
+func (b *BulkWrite) Execute(ctx context.Context) (_ *CollectionBulkWriteResult, err error) {
+	ctx, op := b.collection.startOperation(ctx, "BulkWrite")
+	defer op.end(&err)
+
+	ctx, cancel := b.collection.queryContext(ctx)
+	defer cancel()
+
+	var result *mongo.BulkWriteResult
+	err = op.retry(ctx, false, func() (err error) {
+		result, err = b.collection.collection.BulkWrite(ctx, b.models, options.BulkWrite().SetOrdered(b.ordered))
+		return err
+	})
+	if result == nil {
+		return nil, wrapMongoError(err)
+	}
+
+	bulkResult := &CollectionBulkWriteResult{
+		InsertedCount: int(result.InsertedCount),
+		MatchedCount:  int(result.MatchedCount),
+		ModifiedCount: int(result.ModifiedCount),
+		DeletedCount:  int(result.DeletedCount),
+		UpsertedCount: int(result.UpsertedCount),
+		UpsertedIDs:   make(map[int]interface{}, len(result.UpsertedIDs)),
+	}
+	for i, id := range result.UpsertedIDs {
+		bulkResult.UpsertedIDs[int(i)] = id
+	}
+	op.modified = int(result.InsertedCount + result.ModifiedCount + result.DeletedCount + result.UpsertedCount)
+
+	bulkResult.WriteErrors = WriteErrors(err)
+
+	return bulkResult, wrapMongoError(err)
+}
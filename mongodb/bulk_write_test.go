@@ -0,0 +1,90 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBulkWrite(t *testing.T) {
+	Convey("Given a mongo server cluster", t, func() {
+		ctx := context.Background()
+		conn, cleanup := setupMongoConnection(t)
+		defer cleanup(ctx)
+
+		Convey("setup with test objects in test-collection-1", func() {
+			if err := conn.DropDatabase(ctx); err != nil {
+				t.Fatalf("failed to drop database: %v", err)
+			}
+			setupTest(t, conn, collection1, simpleObject{ID: 1, State: "first"})
+			setupTest(t, conn, collection1, simpleObject{ID: 2, State: "first"})
+			collection := conn.Collection(collection1)
+
+			Convey("When a bulk write mixing all operation types is executed", func() {
+				res, err := collection.BulkWrite().
+					InsertOne(simpleObject{ID: 3, State: "third"}).
+					UpdateOne(bson.M{"_id": 1}, bson.M{"$set": bson.M{"state": "second"}}).
+					UpsertOne(bson.M{"_id": 4}, bson.M{"$set": bson.M{"state": "fourth"}}).
+					ReplaceOne(bson.M{"_id": 3}, simpleObject{ID: 3, State: "replaced"}).
+					DeleteOne(bson.M{"_id": 2}).
+					Execute(ctx)
+
+				Convey("Then all the operations are applied and the counts reported", func() {
+					So(err, ShouldBeNil)
+					So(res.InsertedCount, ShouldEqual, 1)
+					So(res.MatchedCount, ShouldEqual, 2)
+					So(res.ModifiedCount, ShouldEqual, 2)
+					So(res.UpsertedCount, ShouldEqual, 1)
+					So(res.UpsertedIDs, ShouldResemble, map[int]interface{}{2: int32(4)})
+					So(res.DeletedCount, ShouldEqual, 1)
+					So(res.WriteErrors, ShouldBeEmpty)
+
+					var objs []simpleObject
+					So(queryCursor(conn, collection1, bson.M{}, &objs), ShouldBeNil)
+					So(objs, ShouldResemble, []simpleObject{{ID: 1, State: "second"}, {ID: 3, State: "replaced"}, {ID: 4, State: "fourth"}})
+				})
+			})
+
+			Convey("When an ordered bulk write with a failing operation is executed", func() {
+				res, err := collection.BulkWrite().
+					InsertOne(simpleObject{ID: 1, State: "duplicate"}).
+					InsertOne(simpleObject{ID: 3, State: "third"}).
+					Execute(ctx)
+
+				Convey("Then execution stops at the failing operation, which is reported", func() {
+					So(err, ShouldNotBeNil)
+					So(res.InsertedCount, ShouldEqual, 0)
+					So(res.WriteErrors, ShouldHaveLength, 1)
+					So(res.WriteErrors[0].Index, ShouldEqual, 0)
+					So(res.WriteErrors[0].Code, ShouldEqual, 11000)
+				})
+			})
+
+			Convey("When an unordered bulk write with a failing operation is executed", func() {
+				res, err := collection.BulkWrite().Unordered().
+					InsertOne(simpleObject{ID: 1, State: "duplicate"}).
+					InsertOne(simpleObject{ID: 3, State: "third"}).
+					Execute(ctx)
+
+				Convey("Then the other operations are applied, and the failing operation is reported", func() {
+					So(err, ShouldNotBeNil)
+					So(res.InsertedCount, ShouldEqual, 1)
+					So(res.WriteErrors, ShouldHaveLength, 1)
+					So(res.WriteErrors[0].Index, ShouldEqual, 0)
+				})
+			})
+
+			Convey("When an empty bulk write is executed", func() {
+				res, err := collection.BulkWrite().Execute(ctx)
+
+				Convey("Then an error is returned", func() {
+					So(err, ShouldNotBeNil)
+					So(res, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
@@ -2,18 +2,21 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
 	lock "github.com/square/mongo-lock"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/trace"
 )
 
 // Collection is a handle to a MongoDB collection
 type Collection struct {
-	collection *mongo.Collection
+	collection             *mongo.Collection
+	queryTimeout           time.Duration
+	traceFilterShapes      bool
+	slowOperationThreshold time.Duration
+	retryPolicy            retryPolicy
 }
 
 // CollectionInsertManyResult is the result type returned from InsertMany operations.
@@ -46,15 +49,59 @@ type Cursor interface {
 	Err() error
 }
 
-func getSpan(ctx context.Context, spanName string) trace.Span {
-	tracer := otel.GetTracerProvider().Tracer("dp-mongodb")
-	_, span := tracer.Start(ctx, spanName)
-	return span
-}
+// CollectionAPI is the interface of the operations on a collection, implemented by Collection, and by the in-memory
+// fake in the mongotest package
+type CollectionAPI interface {
+	Distinct(ctx context.Context, fieldName string, filter interface{}) ([]interface{}, error)
+	Count(ctx context.Context, filter interface{}, opts ...FindOption) (int, error)
+	Find(ctx context.Context, filter, results interface{}, opts ...FindOption) (int, error)
+	FindOne(ctx context.Context, filter interface{}, result interface{}, opts ...FindOption) error
+	FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, result interface{}, opts ...FindOption) error
+	Insert(ctx context.Context, document interface{}) (*CollectionInsertResult, error)
+	InsertOne(ctx context.Context, document interface{}) (*CollectionInsertResult, error)
+	InsertMany(ctx context.Context, documents []interface{}) (*CollectionInsertManyResult, error)
+	Upsert(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpsertById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpsertOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpdateById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error)
+	Update(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpdateOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpdateMany(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	Delete(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+	DeleteById(ctx context.Context, id interface{}) (*CollectionDeleteResult, error)
+	DeleteOne(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+	DeleteMany(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+	Aggregate(ctx context.Context, pipeline, results interface{}) error
+}
+
+//go:generate moq -out mock/collection.go -pkg mock . Collector
+
+// Collector is the interface of all the operations of a Collection, including those (such as change streams and
+// index management) that are specific to a MongoDB server
+type Collector interface {
+	CollectionAPI
+	Must() *Must
+	MustAPI() MustAPI
+	FindCursor(ctx context.Context, filter interface{}, opts ...FindOption) (Cursor, error)
+	FindPage(ctx context.Context, filter interface{}, results interface{}, token string, opts ...FindOption) (*CollectionPage, error)
+	BulkWrite() *BulkWrite
+	Watch(ctx context.Context, pipeline interface{}, opts ...WatchOption) (*ChangeStream, error)
+	ListIndexes(ctx context.Context) ([]IndexSpec, error)
+	EnsureIndexes(ctx context.Context, specs []IndexSpec, opts ...IndexOption) (*EnsureIndexesResult, error)
+	ExplainFind(ctx context.Context, filter interface{}, opts ...FindOption) (*ExplainPlan, error)
+	ExplainCount(ctx context.Context, filter interface{}, opts ...FindOption) (*ExplainPlan, error)
+	ExplainAggregate(ctx context.Context, pipeline interface{}) (*ExplainPlan, error)
+	NewLockClient() *lock.Client
+}
+
+var (
+	_ CollectionAPI = (*Collection)(nil)
+	_ Collector     = (*Collection)(nil)
+)
 
 // NewCollection creates a new collection
 func NewCollection(collection *mongo.Collection) *Collection {
-	return &Collection{collection}
+	return &Collection{collection: collection}
 }
 
 // Must creates a new Must for the collection
@@ -62,23 +109,44 @@ func (c *Collection) Must() *Must {
 	return newMust(c)
 }
 
+// MustAPI creates a new Must for the collection as a MustAPI, so that a Collector can be mocked to return a mock Must
+func (c *Collection) MustAPI() MustAPI {
+	return c.Must()
+}
+
 // Distinct returns the list of distinct values for the given field name in the collection
-func (c *Collection) Distinct(ctx context.Context, fieldName string, filter interface{}) ([]interface{}, error) {
-	span := getSpan(ctx, "collection.Distinct")
-	defer span.End()
+func (c *Collection) Distinct(ctx context.Context, fieldName string, filter interface{}) (results []interface{}, err error) {
+	ctx, op := c.startOperation(ctx, "Distinct")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
 
-	results, err := c.collection.Distinct(ctx, fieldName, filter)
+	err = op.retry(ctx, true, func() (err error) {
+		results, err = c.collection.Distinct(ctx, fieldName, filter)
+		return err
+	})
+	op.returned = len(results)
 
 	return results, wrapMongoError(err)
 }
 
 // Count returns the number of documents in the collection that satisfy the given filter (which cannot be nil)
 // Sort and Projection options are ignored. A Limit option <=0 is ignored, and a count of all documents is returned
-func (c *Collection) Count(ctx context.Context, filter interface{}, opts ...FindOption) (int, error) {
-	span := getSpan(ctx, "collection.Count")
-	defer span.End()
+func (c *Collection) Count(ctx context.Context, filter interface{}, opts ...FindOption) (_ int, err error) {
+	ctx, op := c.startOperation(ctx, "Count")
+	defer op.end(&err)
+	op.filter(filter)
 
-	count, err := c.collection.CountDocuments(ctx, filter, newFindOptions(opts...).asDriverCountOption())
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err = op.retry(ctx, true, func() (err error) {
+		count, err = c.collection.CountDocuments(ctx, filter, newFindOptions(opts...).asDriverCountOption())
+		return err
+	})
 
 	return int(count), wrapMongoError(err)
 }
@@ -86,47 +154,111 @@ func (c *Collection) Count(ctx context.Context, filter interface{}, opts ...Find
 // Find returns the total number of documents in the collection that satisfy the given filter (restricted by the
 // given options), with the actual documents provided in the results parameter (which must be a non nil pointer
 // to a slice of the expected document type)
+// The total count and the documents are queried concurrently, unless the context carries a session (since a session
+// cannot be used concurrently), in which case they are queried in turn
+// If the WithoutTotalCount option is given, the total count is not queried and -1 is returned in its place
 // If no sort order option is provided a default sort order of 'ascending _id' is used (bson.M{"_id": 1})
-func (c *Collection) Find(ctx context.Context, filter, results interface{}, opts ...FindOption) (int, error) {
-	span := getSpan(ctx, "collection.Find")
-	defer span.End()
+func (c *Collection) Find(ctx context.Context, filter, results interface{}, opts ...FindOption) (_ int, err error) {
+	ctx, op := c.startOperation(ctx, "Find")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
 
 	fo := newFindOptions(opts...)
 
-	tc, err := c.collection.CountDocuments(ctx, filter)
-	switch {
-	case err != nil:
-		return 0, err
-	case tc == 0:
-		return 0, nil
-	case fo.limit < 0 || fo.skip >= tc,
-		fo.limit == 0 && fo.obeyZeroLimit:
-		return int(tc), nil
+	totalCount := c.countDocuments(ctx, op, filter, fo)
+	if fo.limit < 0 || (fo.limit == 0 && fo.obeyZeroLimit) {
+		tc, err := totalCount()
+		return tc, wrapMongoError(err)
 	}
 
 	if fo.sort == nil {
 		fo.sort = bson.M{"_id": 1}
 	}
-	cursor, err := c.collection.Find(ctx, filter, fo.asDriverFindOption())
+	op.sort(fo.sort)
+	err = op.retry(ctx, true, func() error {
+		cursor, err := c.collection.Find(ctx, filter, fo.asDriverFindOption())
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, results)
+	})
 	if err != nil {
 		return 0, wrapMongoError(err)
 	}
+	op.returned = resultsLen(results)
 
-	return int(tc), wrapMongoError(cursor.All(ctx, results))
+	tc, err := totalCount()
+	if err != nil {
+		return 0, wrapMongoError(err)
+	}
+
+	return tc, nil
+}
+
+// countDocuments starts counting the documents in the collection that satisfy the given filter, returning a function
+// that waits for and returns the count. The count is run concurrently unless the context carries a session
+// If the WithoutTotalCount option was given, no count is run and the function returns -1
+func (c *Collection) countDocuments(ctx context.Context, op *operation, filter interface{}, fo *findOptions) func() (int, error) {
+	if fo.withoutTotalCount {
+		return func() (int, error) { return -1, nil }
+	}
+
+	count := func() (tc int64, err error) {
+		err = op.retry(ctx, true, func() (err error) {
+			tc, err = c.collection.CountDocuments(ctx, filter)
+			return err
+		})
+		return tc, err
+	}
+
+	if mongo.SessionFromContext(ctx) != nil {
+		tc, err := count()
+		return func() (int, error) { return int(tc), err }
+	}
+
+	type countResult struct {
+		count int64
+		err   error
+	}
+	result := make(chan countResult, 1)
+	go func() {
+		tc, err := count()
+		result <- countResult{tc, err}
+	}()
+
+	return func() (int, error) {
+		r := <-result
+		return int(r.count), r.err
+	}
 }
 
 // FindOne returns a single document in the collection that satisfies the given filter (restricted by the
 // given options), with the actual document provided in the result parameter (which must be a non nil pointer
 // to a document of the expected type)
 // If no document could be found, an ErrNoDocumentFound error is returned
-func (c *Collection) FindOne(ctx context.Context, filter interface{}, result interface{}, opts ...FindOption) error {
-	span := getSpan(ctx, "collection.FindOne")
-	defer span.End()
+func (c *Collection) FindOne(ctx context.Context, filter interface{}, result interface{}, opts ...FindOption) (err error) {
+	ctx, op := c.startOperation(ctx, "FindOne")
+	defer op.end(&err)
+	op.filter(filter)
 
-	r := c.collection.FindOne(ctx, filter, newFindOptions(opts...).asDriverFindOneOption())
-	if r.Err() != nil {
-		return wrapMongoError(r.Err())
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	fo := newFindOptions(opts...)
+	op.sort(fo.sort)
+
+	var r *mongo.SingleResult
+	err = op.retry(ctx, true, func() error {
+		r = c.collection.FindOne(ctx, filter, fo.asDriverFindOneOption())
+		return r.Err()
+	})
+	if err != nil {
+		return wrapMongoError(err)
 	}
+	op.returned = 1
 
 	return wrapMongoError(r.Decode(result))
 }
@@ -136,29 +268,52 @@ func (c *Collection) FindOne(ctx context.Context, filter interface{}, result int
 // to a document of the expected type)
 // It will also process the update found in the update parameter - this allows atomic read-modify-write operations
 // If no document could be found, an ErrNoDocumentFound error is returned
-func (c *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, result interface{}, opts ...FindOption) error {
-	span := getSpan(ctx, "collection.FindOneAndUpdate")
-	defer span.End()
+func (c *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, result interface{}, opts ...FindOption) (err error) {
+	ctx, op := c.startOperation(ctx, "FindOneAndUpdate")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
 
-	r := c.collection.FindOneAndUpdate(ctx, filter, update, newFindOptions(opts...).asDriverFindOneAndUpdateOption())
-	if r.Err() != nil {
-		return wrapMongoError(r.Err())
+	fo := newFindOptions(opts...)
+	op.sort(fo.sort)
+
+	var r *mongo.SingleResult
+	err = op.retry(ctx, false, func() error {
+		r = c.collection.FindOneAndUpdate(ctx, filter, update, fo.asDriverFindOneAndUpdateOption())
+		return r.Err()
+	})
+	if err != nil {
+		return wrapMongoError(err)
 	}
+	op.returned, op.modified = 1, 1
 
 	return wrapMongoError(r.Decode(result))
 }
 
 // FindCursor returns a mongo cursor iterating over the collection
+// The query timeout applies to the initial query only; subsequent iteration of the cursor is bounded by the context
+// passed to the cursor's methods
 // If no sort order option is provided a default sort order of 'ascending _id' is used (bson.M{"_id": 1})
-func (c *Collection) FindCursor(ctx context.Context, filter interface{}, opts ...FindOption) (Cursor, error) {
-	span := getSpan(ctx, "collection.FindCursor")
-	defer span.End()
+func (c *Collection) FindCursor(ctx context.Context, filter interface{}, opts ...FindOption) (_ Cursor, err error) {
+	ctx, op := c.startOperation(ctx, "FindCursor")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
 
 	fo := newFindOptions(opts...)
 	if fo.sort == nil {
 		fo.sort = bson.M{"_id": 1}
 	}
-	cursor, err := c.collection.Find(ctx, filter, fo.asDriverFindOption())
+	op.sort(fo.sort)
+	var cursor *mongo.Cursor
+	err = op.retry(ctx, true, func() (err error) {
+		cursor, err = c.collection.Find(ctx, filter, fo.asDriverFindOption())
+		return err
+	})
 	if err != nil {
 		return nil, wrapMongoError(err)
 	}
@@ -169,7 +324,7 @@ func (c *Collection) FindCursor(ctx context.Context, filter interface{}, opts ..
 // Insert creates a single document in the collection
 // Deprecated: Use InsertOne
 func (c *Collection) Insert(ctx context.Context, document interface{}) (*CollectionInsertResult, error) {
-	span := getSpan(ctx, "collection.Insert")
+	ctx, span := c.startSpan(ctx, "collection.Insert", "Insert")
 	defer span.End()
 	return c.InsertOne(ctx, document)
 }
@@ -178,13 +333,22 @@ func (c *Collection) Insert(ctx context.Context, document interface{}) (*Collect
 // The document must be the document to be inserted and cannot be nil.
 // If the document does not have an _id field when transformed into BSON, one will be added automatically to the marshalled document.
 // The _id can be retrieved from the InsertedId field of the returned CollectionInsertResult.
-func (c *Collection) InsertOne(ctx context.Context, document interface{}) (*CollectionInsertResult, error) {
-	span := getSpan(ctx, "InsertOne")
-	defer span.End()
-	result, err := c.collection.InsertOne(ctx, document)
+func (c *Collection) InsertOne(ctx context.Context, document interface{}) (_ *CollectionInsertResult, err error) {
+	ctx, op := c.startOperation(ctx, "InsertOne")
+	defer op.end(&err)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var result *mongo.InsertOneResult
+	err = op.retry(ctx, false, func() (err error) {
+		result, err = c.collection.InsertOne(ctx, document)
+		return err
+	})
 	if err != nil {
 		return nil, wrapMongoError(err)
 	}
+	op.modified = 1
 
 	return &CollectionInsertResult{result.InsertedID}, nil
 }
@@ -193,13 +357,22 @@ func (c *Collection) InsertOne(ctx context.Context, document interface{}) (*Coll
 // The documents must be a slice of documents to insert and slice cannot be nil or empty. The elements must all be non-nil.
 // For any document that does not have an _id field when transformed into BSON, one will be added automatically to the marshalled document.
 // The _id values for the inserted documents can be retrieved from the InsertedIds field of the returned CollectionInsertManyResult.
-func (c *Collection) InsertMany(ctx context.Context, documents []interface{}) (*CollectionInsertManyResult, error) {
-	span := getSpan(ctx, "collection.InsertMany")
-	defer span.End()
-	result, err := c.collection.InsertMany(ctx, documents)
+func (c *Collection) InsertMany(ctx context.Context, documents []interface{}) (_ *CollectionInsertManyResult, err error) {
+	ctx, op := c.startOperation(ctx, "InsertMany")
+	defer op.end(&err)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var result *mongo.InsertManyResult
+	err = op.retry(ctx, false, func() (err error) {
+		result, err = c.collection.InsertMany(ctx, documents)
+		return err
+	})
 	if err != nil {
 		return nil, wrapMongoError(err)
 	}
+	op.modified = len(result.InsertedIDs)
 
 	insertResult := &CollectionInsertManyResult{}
 	insertResult.InsertedIds = result.InsertedIDs
@@ -210,7 +383,7 @@ func (c *Collection) InsertMany(ctx context.Context, documents []interface{}) (*
 // Upsert creates or updates a document located by the provided selector
 // Deprecated: Use UpsertOne
 func (c *Collection) Upsert(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.Upsert")
+	ctx, span := c.startSpan(ctx, "collection.Upsert", "Upsert")
 	defer span.End()
 	return c.UpsertOne(ctx, selector, update)
 }
@@ -218,7 +391,7 @@ func (c *Collection) Upsert(ctx context.Context, selector interface{}, update in
 // UpsertById creates or updates a document located by the provided id selector
 // Deprecated: Use UpsertOne
 func (c *Collection) UpsertById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.UpsertById")
+	ctx, span := c.startSpan(ctx, "collection.UpsertById", "UpsertById")
 	defer span.End()
 	return c.UpsertOne(ctx, bson.M{"_id": id}, update)
 }
@@ -229,15 +402,13 @@ func (c *Collection) UpsertById(ctx context.Context, id interface{}, update inte
 // If the selector does not match any documents, the update document is inserted into the collection.
 // If the selector matches multiple documents, one will be selected from the matched set, updated and a CollectionUpdateResult with a MatchedCount of 1 will be returned.
 func (c *Collection) UpsertOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.UpsertOne")
-	defer span.End()
-	return c.updateRecord(ctx, selector, update, true)
+	return c.updateRecord(ctx, "UpsertOne", selector, update, true)
 }
 
 // UpdateById modifies a single document located by the provided id selector
 // Deprecated: Use UpdateOne
 func (c *Collection) UpdateById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.UpdateById")
+	ctx, span := c.startSpan(ctx, "collection.UpdateById", "UpdateById")
 	defer span.End()
 	return c.UpdateOne(ctx, bson.M{"_id": id}, update)
 }
@@ -245,7 +416,7 @@ func (c *Collection) UpdateById(ctx context.Context, id interface{}, update inte
 // Update modifies a single document located by the provided selector
 // Deprecated: Use UpdateOne
 func (c *Collection) Update(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.Update")
+	ctx, span := c.startSpan(ctx, "collection.Update", "Update")
 	defer span.End()
 	return c.UpdateOne(ctx, selector, update)
 }
@@ -256,20 +427,28 @@ func (c *Collection) Update(ctx context.Context, selector interface{}, update in
 // If the selector does not match any documents, the operation will succeed and a CollectionUpdateResult with a MatchedCount of 0 will be returned.
 // If the selector matches multiple documents, one will be selected from the matched set, updated and a CollectionUpdateResult with a MatchedCount of 1 will be returned.
 func (c *Collection) UpdateOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "collection.UpdateOne")
-	defer span.End()
-	return c.updateRecord(ctx, selector, update, false)
+	return c.updateRecord(ctx, "UpdateOne", selector, update, false)
 }
 
 // UpdateMany modifies multiple documents located by the provided selector
 // The selector must be a document containing query operators and cannot be nil.
 // The update must be a document containing update operators and cannot be nil or empty.
 // If the selector does not match any documents, the operation will succeed and a CollectionUpdateResult with a MatchedCount of 0 will be returned.
-func (c *Collection) UpdateMany(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "UpdateMany")
-	defer span.End()
-	updateResult, err := c.collection.UpdateMany(ctx, selector, update, options.Update())
+func (c *Collection) UpdateMany(ctx context.Context, selector interface{}, update interface{}) (_ *CollectionUpdateResult, err error) {
+	ctx, op := c.startOperation(ctx, "UpdateMany")
+	defer op.end(&err)
+	op.filter(selector)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var updateResult *mongo.UpdateResult
+	err = op.retry(ctx, false, func() (err error) {
+		updateResult, err = c.collection.UpdateMany(ctx, selector, update, options.Update())
+		return err
+	})
 	if err == nil {
+		op.modified = int(updateResult.ModifiedCount)
 		return &CollectionUpdateResult{
 			MatchedCount:  int(updateResult.MatchedCount),
 			ModifiedCount: int(updateResult.ModifiedCount),
@@ -279,15 +458,27 @@ func (c *Collection) UpdateMany(ctx context.Context, selector interface{}, updat
 	return nil, wrapMongoError(err)
 }
 
-func (c *Collection) updateRecord(ctx context.Context, selector interface{}, update interface{}, upsert bool) (*CollectionUpdateResult, error) {
+func (c *Collection) updateRecord(ctx context.Context, name string, selector interface{}, update interface{}, upsert bool) (_ *CollectionUpdateResult, err error) {
+	ctx, op := c.startOperation(ctx, name)
+	defer op.end(&err)
+	op.filter(selector)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
 	opts := options.Update()
 
 	if upsert {
 		opts.SetUpsert(true)
 	}
 
-	updateResult, err := c.collection.UpdateOne(ctx, selector, update, opts)
+	var updateResult *mongo.UpdateResult
+	err = op.retry(ctx, false, func() (err error) {
+		updateResult, err = c.collection.UpdateOne(ctx, selector, update, opts)
+		return err
+	})
 	if err == nil {
+		op.modified = int(updateResult.ModifiedCount + updateResult.UpsertedCount)
 		return &CollectionUpdateResult{
 			MatchedCount:  int(updateResult.MatchedCount),
 			ModifiedCount: int(updateResult.ModifiedCount),
@@ -302,7 +493,7 @@ func (c *Collection) updateRecord(ctx context.Context, selector interface{}, upd
 // Delete deletes a single document based on the provided selector
 // Deprecated: Use DeleteOne instead
 func (c *Collection) Delete(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "collection.Delete")
+	ctx, span := c.startSpan(ctx, "collection.Delete", "Delete")
 	defer span.End()
 	return c.DeleteOne(ctx, selector)
 }
@@ -310,7 +501,7 @@ func (c *Collection) Delete(ctx context.Context, selector interface{}) (*Collect
 // DeleteById deletes a document based on the provided id selector
 // Deprecated: Use DeleteOne
 func (c *Collection) DeleteById(ctx context.Context, id interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "collection.DeleteById")
+	ctx, span := c.startSpan(ctx, "collection.DeleteById", "DeleteById")
 	defer span.End()
 	return c.DeleteOne(ctx, bson.M{"_id": id})
 }
@@ -319,13 +510,23 @@ func (c *Collection) DeleteById(ctx context.Context, id interface{}) (*Collectio
 // The selector must be a document containing query operators and cannot be nil.
 // If the selector does not match any documents, the operation will succeed and a CollectionDeleteResult with a DeletedCount of 0 will be returned.
 // If the selector matches multiple documents, one will be selected from the matched set, deleted and a CollectionDeleteResult with a DeletedCount of 1 will be returned.
-func (c *Collection) DeleteOne(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "collection.DeleteOne")
-	defer span.End()
-	result, err := c.collection.DeleteOne(ctx, selector)
+func (c *Collection) DeleteOne(ctx context.Context, selector interface{}) (_ *CollectionDeleteResult, err error) {
+	ctx, op := c.startOperation(ctx, "DeleteOne")
+	defer op.end(&err)
+	op.filter(selector)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var result *mongo.DeleteResult
+	err = op.retry(ctx, false, func() (err error) {
+		result, err = c.collection.DeleteOne(ctx, selector)
+		return err
+	})
 	if err != nil {
 		return nil, wrapMongoError(err)
 	}
+	op.modified = int(result.DeletedCount)
 
 	return &CollectionDeleteResult{int(result.DeletedCount)}, nil
 }
@@ -333,27 +534,47 @@ func (c *Collection) DeleteOne(ctx context.Context, selector interface{}) (*Coll
 // DeleteMany deletes multiple documents based on the provided selector
 // The selector must be a document containing query operators and cannot be nil.
 // If the selector does not match any documents, the operation will succeed and a CollectionDeleteResult with a DeletedCount of 0 will be returned.
-func (c *Collection) DeleteMany(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "collection.DeleteMany")
-	defer span.End()
-	result, err := c.collection.DeleteMany(ctx, selector)
+func (c *Collection) DeleteMany(ctx context.Context, selector interface{}) (_ *CollectionDeleteResult, err error) {
+	ctx, op := c.startOperation(ctx, "DeleteMany")
+	defer op.end(&err)
+	op.filter(selector)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	var result *mongo.DeleteResult
+	err = op.retry(ctx, false, func() (err error) {
+		result, err = c.collection.DeleteMany(ctx, selector)
+		return err
+	})
 	if err != nil {
 		return nil, wrapMongoError(err)
 	}
+	op.modified = int(result.DeletedCount)
 
 	return &CollectionDeleteResult{int(result.DeletedCount)}, nil
 }
 
 // Aggregate starts a pipeline operation
-func (c *Collection) Aggregate(ctx context.Context, pipeline, results interface{}) error {
-	cursor, err := c.collection.Aggregate(ctx, pipeline)
+func (c *Collection) Aggregate(ctx context.Context, pipeline, results interface{}) (err error) {
+	ctx, op := c.startOperation(ctx, "Aggregate")
+	defer op.end(&err)
+	op.filter(pipeline)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	err = op.retry(ctx, isReadOnlyPipeline(pipeline), func() error {
+		cursor, err := c.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, results)
+	})
 	if err != nil {
 		return wrapMongoError(err)
 	}
-
-	if err = cursor.All(ctx, results); err != nil {
-		return wrapMongoError(err)
-	}
+	op.returned = resultsLen(results)
 
 	return nil
 }
@@ -143,6 +143,24 @@ func TestCollectionSuite(t *testing.T) {
 					So(res, ShouldResemble, []TestModel{{ID: 2, State: "second"}})
 				})
 
+				Convey("Find returns the expected documents without a total count when the WithoutTotalCount option is given", func() {
+					res := []TestModel{}
+
+					n, err := conn.Collection(collection).Find(context.Background(), bson.M{"state": "first"}, &res, mongoDriver.WithoutTotalCount())
+					So(err, ShouldBeNil)
+					So(n, ShouldEqual, -1)
+					So(res, ShouldResemble, []TestModel{{ID: 1, State: "first"}, {ID: 3, State: "first"}})
+				})
+
+				Convey("Find returns the total count and an empty result when the offset is beyond the documents that satisfy the given filter", func() {
+					res := []TestModel{}
+
+					n, err := conn.Collection(collection).Find(context.Background(), bson.M{"state": "first"}, &res, mongoDriver.Offset(5))
+					So(err, ShouldBeNil)
+					So(n, ShouldEqual, 2)
+					So(res, ShouldResemble, []TestModel{})
+				})
+
 				Convey("FindOne returns an ErrNoDocumentFound error when no documents exist which satisfy the given filter", func() {
 					res := TestModel{}
 					err := conn.Collection(collection).FindOne(context.Background(), bson.M{"state": "third"}, &res)
@@ -202,6 +220,129 @@ func TestCollectionSuite(t *testing.T) {
 					So(n.Close(ctx), ShouldBeNil)
 				})
 
+				Convey("FindPage returns successive pages of the documents that satisfy the given filter", func() {
+					res := []TestModel{}
+
+					page, err := conn.Collection(collection).FindPage(ctx, bson.M{}, &res, "", mongoDriver.Limit(2))
+					So(err, ShouldBeNil)
+					So(page.TotalCount, ShouldEqual, 3)
+					So(page.NextToken, ShouldNotBeEmpty)
+					So(res, ShouldResemble, []TestModel{{ID: 1, State: "first"}, {ID: 2, State: "second"}})
+
+					page, err = conn.Collection(collection).FindPage(ctx, bson.M{}, &res, page.NextToken, mongoDriver.Limit(2), mongoDriver.WithoutTotalCount())
+					So(err, ShouldBeNil)
+					So(page.TotalCount, ShouldEqual, -1)
+					So(page.NextToken, ShouldBeEmpty)
+					So(res, ShouldResemble, []TestModel{{ID: 3, State: "first"}})
+				})
+
+				Convey("FindPage pages through the documents in the given sort order", func() {
+					res := []TestModel{}
+					sort := bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: -1}}
+
+					page, err := conn.Collection(collection).FindPage(ctx, bson.M{}, &res, "", mongoDriver.Limit(2), mongoDriver.Sort(sort))
+					So(err, ShouldBeNil)
+					So(res, ShouldResemble, []TestModel{{ID: 3, State: "first"}, {ID: 1, State: "first"}})
+
+					page, err = conn.Collection(collection).FindPage(ctx, bson.M{}, &res, page.NextToken, mongoDriver.Limit(2), mongoDriver.Sort(sort))
+					So(err, ShouldBeNil)
+					So(page.NextToken, ShouldBeEmpty)
+					So(res, ShouldResemble, []TestModel{{ID: 2, State: "second"}})
+				})
+
+				Convey("FindPage returns an error, rather than a token for the same page, if the projection excludes _id", func() {
+					res := []bson.M{}
+					_, err := conn.Collection(collection).FindPage(ctx, bson.M{}, &res, "", mongoDriver.Limit(2), mongoDriver.Projection(bson.M{"_id": 0}))
+					So(errors.Is(err, mongoDriver.ErrInvalidPageSortValue), ShouldBeTrue)
+				})
+
+				Convey("FindPage returns an error if no limit is given", func() {
+					res := []TestModel{}
+					_, err := conn.Collection(collection).FindPage(ctx, bson.M{}, &res, "")
+					So(err, ShouldEqual, mongoDriver.ErrInvalidPageLimit)
+				})
+
+			})
+
+			Convey("setup with test data for testing index functionality", func() {
+				testData := []TestModel{{ID: 1, State: "first"}}
+
+				if err := setUpTestData(ctx, conn, collection, testData); err != nil {
+					t.Fatalf("failed to insert test data, skipping tests: %v", err)
+				}
+
+				ttl := time.Hour
+				specs := []mongoDriver.IndexSpec{
+					{Keys: bson.D{{Key: "state", Value: 1}}},
+					{Name: "expiry", Keys: bson.D{{Key: "last_updated", Value: 1}}, ExpireAfter: &ttl},
+				}
+
+				Convey("EnsureIndexes creates the declared indexes that do not exist", func() {
+					res, err := conn.Collection(collection).EnsureIndexes(ctx, specs)
+					So(err, ShouldBeNil)
+					So(res.Created, ShouldResemble, []string{"state_1", "expiry"})
+					So(res.Undeclared, ShouldBeEmpty)
+
+					indexes, err := conn.Collection(collection).ListIndexes(ctx)
+					So(err, ShouldBeNil)
+					So(indexes, ShouldHaveLength, 3)
+					So(indexes[1].Name, ShouldEqual, "state_1")
+					So(indexes[2].Name, ShouldEqual, "expiry")
+					So(*indexes[2].ExpireAfter, ShouldEqual, time.Hour)
+
+					Convey("and creates nothing when called again with the same declarations", func() {
+						res, err := conn.Collection(collection).EnsureIndexes(ctx, specs)
+						So(err, ShouldBeNil)
+						So(res.Created, ShouldBeEmpty)
+					})
+
+					Convey("and reports, and optionally drops, indexes that are no longer declared", func() {
+						res, err := conn.Collection(collection).EnsureIndexes(ctx, specs[:1])
+						So(err, ShouldBeNil)
+						So(res.Undeclared, ShouldResemble, []string{"expiry"})
+						So(res.Dropped, ShouldBeEmpty)
+
+						res, err = conn.Collection(collection).EnsureIndexes(ctx, specs[:1], mongoDriver.DropUndeclaredIndexes())
+						So(err, ShouldBeNil)
+						So(res.Dropped, ShouldResemble, []string{"expiry"})
+
+						indexes, err := conn.Collection(collection).ListIndexes(ctx)
+						So(err, ShouldBeNil)
+						So(indexes, ShouldHaveLength, 2)
+					})
+
+					Convey("and returns an ErrIndexConflict error if a declared index conflicts with an existing index", func() {
+						_, err := conn.Collection(collection).EnsureIndexes(ctx, []mongoDriver.IndexSpec{{Name: "state_1", Keys: bson.D{{Key: "state", Value: -1}}}})
+						So(errors.Is(err, mongoDriver.ErrIndexConflict), ShouldBeTrue)
+					})
+				})
+
+				Convey("Explain reports whether a query uses an index", func() {
+					_, err := conn.Collection(collection).EnsureIndexes(ctx, specs)
+					So(err, ShouldBeNil)
+
+					plan, err := conn.Collection(collection).ExplainFind(ctx, bson.M{"state": "first"}, mongoDriver.Sort(bson.M{"state": 1}))
+					So(err, ShouldBeNil)
+					So(plan.IsCollectionScan(), ShouldBeFalse)
+					So(plan.HasStage(mongoDriver.StageIndexScan), ShouldBeTrue)
+					So(plan.IndexName(), ShouldEqual, "state_1")
+					So(plan.DocsReturned, ShouldEqual, 1)
+					So(plan.DocsExamined, ShouldEqual, 1)
+
+					plan, err = conn.Collection(collection).ExplainCount(ctx, bson.M{"state": "first"})
+					So(err, ShouldBeNil)
+					So(plan.IsCollectionScan(), ShouldBeFalse)
+					So(plan.IndexName(), ShouldEqual, "state_1")
+
+					plan, err = conn.Collection(collection).ExplainAggregate(ctx, bson.A{bson.M{"$match": bson.M{"state": "first"}}, bson.M{"$group": bson.M{"_id": "$state"}}})
+					So(err, ShouldBeNil)
+					So(plan.IndexName(), ShouldEqual, "state_1")
+
+					plan, err = conn.Collection(collection).ExplainFind(ctx, bson.M{"unindexed": "value"}, mongoDriver.Sort(bson.M{"unindexed": 1}))
+					So(err, ShouldBeNil)
+					So(plan.IsCollectionScan(), ShouldBeTrue)
+					So(plan.IndexName(), ShouldBeEmpty)
+				})
 			})
 
 			Convey("setup with data for testing Insert functionality", func() {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ONSdigital/log.go/v2/log"
@@ -16,18 +17,45 @@ var (
 	timeLeft          = 1000 * time.Millisecond
 )
 
+//go:generate moq -out mock/connector.go -pkg mock . MongoConnector
+
+// MongoConnector is the interface of the operations of a MongoConnection
 type MongoConnector interface {
 	Collection(collection string) *Collection
+	CollectionFor(database string, collection string) *Collection
+	Collector(collection string) Collector
+	CollectorFor(database string, collection string) Collector
+	CollectionOptionsFor(ctx context.Context, database string, collection string) (*CollectionOptions, error)
 	ListCollectionsFor(ctx context.Context, database string) ([]string, error)
 	DropDatabase(ctx context.Context) error
 	RunCommand(ctx context.Context, runCommand interface{}) error
+	RunAdminCommand(ctx context.Context, command interface{}, result interface{}) error
+	RunTransaction(ctx context.Context, withRetries bool, fn TransactionFunc, opts ...TransactionOption) (interface{}, error)
+	WithSession(ctx context.Context, fn SessionFunc) error
+	Watch(ctx context.Context, pipeline interface{}, opts ...WatchOption) (*ChangeStream, error)
 	Ping(ctx context.Context, timeoutInSeconds time.Duration) error
 	Close(ctx context.Context) error
 }
 
+var _ MongoConnector = (*MongoConnection)(nil)
+
+// CollectionOptions are the options a collection was created with
+type CollectionOptions struct {
+	Capped    bool     `bson:"capped,omitempty"`    // Whether the collection is capped.
+	Size      int64    `bson:"size,omitempty"`      // The maximum size of a capped collection in bytes.
+	Max       int64    `bson:"max,omitempty"`       // The maximum number of documents in a capped collection.
+	Validator bson.Raw `bson:"validator,omitempty"` // The collection's validator, or nil.
+}
+
 type MongoConnection struct {
-	client   *mongo.Client
-	database string
+	client                  *mongo.Client
+	database                string
+	queryTimeout            time.Duration
+	traceFilterShapes       bool
+	slowOperationThreshold  time.Duration
+	retryPolicy             retryPolicy
+	sessionWarningThreshold time.Duration
+	activeSessions          atomic.Int64
 }
 
 func NewMongoConnection(client *mongo.Client, database string) *MongoConnection {
@@ -104,7 +132,24 @@ func (ms *MongoConnection) d() *mongo.Database {
 }
 
 func (ms *MongoConnection) Collection(collection string) *Collection {
-	return NewCollection(ms.d().Collection(collection))
+	c := NewCollection(ms.d().Collection(collection))
+	c.queryTimeout = ms.queryTimeout
+	c.traceFilterShapes = ms.traceFilterShapes
+	c.slowOperationThreshold = ms.slowOperationThreshold
+	c.retryPolicy = ms.retryPolicy
+
+	return c
+}
+
+// Collector returns a handle to the given collection in the configured database as a Collector, so that a
+// MongoConnector can be mocked to return a mock collection
+func (ms *MongoConnection) Collector(collection string) Collector {
+	return ms.Collection(collection)
+}
+
+// CollectorFor returns a handle to the given collection in the given database as a Collector
+func (ms *MongoConnection) CollectorFor(database, collection string) Collector {
+	return ms.CollectionFor(database, collection)
 }
 
 func (ms *MongoConnection) DropDatabase(ctx context.Context) error {
@@ -117,3 +162,46 @@ func (ms *MongoConnection) RunCommand(ctx context.Context, runCommand interface{
 	res := ms.d().RunCommand(ctx, runCommand)
 	return res.Err()
 }
+
+// RunAdminCommand executes the given command against the admin database, decoding the command's response into the
+// result parameter (which must be a non nil pointer to a document of the expected type)
+func (ms *MongoConnection) RunAdminCommand(ctx context.Context, command interface{}, result interface{}) error {
+	res := ms.client.Database("admin").RunCommand(ctx, command)
+	if res.Err() != nil {
+		return wrapMongoError(res.Err())
+	}
+
+	return wrapMongoError(res.Decode(result))
+}
+
+// CollectionFor returns a handle to the given collection in the given database
+func (ms *MongoConnection) CollectionFor(database, collection string) *Collection {
+	c := NewCollection(ms.client.Database(database).Collection(collection))
+	c.queryTimeout = ms.queryTimeout
+	c.traceFilterShapes = ms.traceFilterShapes
+	c.slowOperationThreshold = ms.slowOperationThreshold
+	c.retryPolicy = ms.retryPolicy
+
+	return c
+}
+
+// CollectionOptionsFor returns the options the given collection in the given database was created with
+// If the collection does not exist, an ErrNoDocumentFound error is returned
+func (ms *MongoConnection) CollectionOptionsFor(ctx context.Context, database, collection string) (*CollectionOptions, error) {
+	specs, err := ms.client.Database(database).ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: collection}})
+	if err != nil {
+		return nil, wrapMongoError(err)
+	}
+	if len(specs) == 0 {
+		return nil, ErrNoDocumentFound
+	}
+
+	opts := &CollectionOptions{}
+	if specs[0].Options != nil {
+		if err = bson.Unmarshal(specs[0].Options, opts); err != nil {
+			return nil, wrapMongoError(err)
+		}
+	}
+
+	return opts, nil
+}
@@ -88,6 +88,24 @@ type MongoDriverConfig struct {
 
 	ConnectTimeout time.Duration `envconfig:"MONGODB_CONNECT_TIMEOUT"`
 	QueryTimeout   time.Duration `envconfig:"MONGODB_QUERY_TIMEOUT"`
+	// TraceFilterShapes adds the shape of each operation's filter, with every value replaced by a placeholder for its
+	// type, to the operation's tracing span
+	TraceFilterShapes bool `envconfig:"MONGODB_TRACE_FILTER_SHAPES"`
+	// SlowOperationThreshold, if >0, logs a warning for each Collection operation, and each command sent to the server,
+	// that takes at least this long, with the shapes of its filter and sort order
+	SlowOperationThreshold time.Duration `envconfig:"MONGODB_SLOW_OPERATION_THRESHOLD"`
+	// MaxRetries, if >0, retries a Collection operation that fails with a transient error (such as a network error or
+	// the primary stepping down) up to this many times, with exponential backoff and jitter. Writes are only retried if
+	// called with a context marked by WithRetryableWrites
+	MaxRetries int `envconfig:"MONGODB_MAX_RETRIES"`
+	// RetryBackoff is the backoff before the first retry (default 100ms), doubled for each further retry up to
+	// RetryMaxBackoff (default 5s)
+	RetryBackoff    time.Duration `envconfig:"MONGODB_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `envconfig:"MONGODB_RETRY_MAX_BACKOFF"`
+	// SessionWarningThreshold, if >0, logs a warning for each session started by RunTransaction or WithSession that is
+	// still active after the threshold, and again when it ends, with the number of active sessions, to help find
+	// sessions that are held open or not ended
+	SessionWarningThreshold time.Duration `envconfig:"MONGODB_SESSION_WARNING_THRESHOLD"`
 
 	TLSConnectionConfig
 }
@@ -148,7 +166,8 @@ func Open(m *MongoDriverConfig) (*MongoConnection, error) {
 	mongoClientOptions := options.Client().
 		ApplyURI(connectionUri).
 		SetTLSConfig(tlsConfig).
-		SetRetryWrites(false)
+		SetRetryWrites(false).
+		SetMonitor(NewCommandMonitor(LogSlowCommands(m.SlowOperationThreshold)))
 
 	if m.IsStrongReadConcernEnabled {
 		// For ensuring strong consistency
@@ -191,5 +210,12 @@ func Open(m *MongoDriverConfig) (*MongoConnection, error) {
 		return nil, errors.New(errMessage)
 	}
 
-	return NewMongoConnection(client, m.Database), nil
+	conn := NewMongoConnection(client, m.Database)
+	conn.queryTimeout = m.QueryTimeout
+	conn.traceFilterShapes = m.TraceFilterShapes
+	conn.slowOperationThreshold = m.SlowOperationThreshold
+	conn.retryPolicy = retryPolicy{maxRetries: m.MaxRetries, backoff: m.RetryBackoff, maxBackoff: m.RetryMaxBackoff}
+	conn.sessionWarningThreshold = m.SessionWarningThreshold
+
+	return conn, nil
 }
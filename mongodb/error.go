@@ -1,11 +1,23 @@
 package mongodb
 
 import (
+	"context"
 	"errors"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Codes of server errors
+const (
+	codeWriteConflict             = 112
+	codeDocumentValidationFailure = 121
+	codePrimarySteppedDown        = 189
+	codeNotWritablePrimary        = 10107
+	codeNotPrimaryNoSecondaryOk   = 13435
+	codeNotPrimaryOrSecondary     = 13436
+)
+
 var (
 	ErrDisconnect      = mongo.ErrClientDisconnected
 	ErrNoDocumentFound = mongo.ErrNoDocuments
@@ -23,14 +35,191 @@ func (e Error) Unwrap() error {
 	return e.inner
 }
 
+// WriteError is the failure of a single write of an operation. It can be extracted from an error returned by a
+// Collection write operation with errors.As, or, for all the failed writes, with WriteErrors
+type WriteError struct {
+	Index      int      // The index of the failing document for InsertMany, or operation for BulkWrite, otherwise 0.
+	Code       int      // The server error code.
+	Message    string   // The server error message.
+	KeyPattern bson.Raw // For a duplicate key error, the keys of the violated unique index, e.g. {"name": 1}, if reported.
+	KeyValue   bson.Raw // For a duplicate key error, the duplicated values, e.g. {"name": "x"}, if reported.
+}
+
+func (e WriteError) Error() string {
+	return e.Message
+}
+
+// IsDuplicateKey returns true if the write error is a duplicate key violation
+func (e WriteError) IsDuplicateKey() bool {
+	return e.Code == 11000 || e.Code == 11001 || e.Code == 12582
+}
+
+// Code returns the code of the server error, or 0 if the error is not a server error. For a failed write, the code
+// is that of the first failing write
+func (e Error) Code() int {
+	return ErrorCode(e.inner)
+}
+
+// As sets the target to the first failed write, if the target is a *WriteError and the error is a failed write
+func (e Error) As(target interface{}) bool {
+	if t, ok := target.(*WriteError); ok {
+		if writeErrors := WriteErrors(e.inner); len(writeErrors) > 0 {
+			*t = writeErrors[0]
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrorCode returns the code of the server error, or 0 if the error is not a server error. For a failed write, the
+// code is that of the first failing write, or of the write concern error if no write failed
+func ErrorCode(err error) int {
+	var (
+		cmdErr   mongo.CommandError
+		writeErr mongo.WriteException
+		bulkErr  mongo.BulkWriteException
+	)
+
+	switch {
+	case errors.As(err, &cmdErr):
+		return int(cmdErr.Code)
+	case errors.As(err, &writeErr):
+		if len(writeErr.WriteErrors) > 0 {
+			return writeErr.WriteErrors[0].Code
+		}
+		if writeErr.WriteConcernError != nil {
+			return writeErr.WriteConcernError.Code
+		}
+	case errors.As(err, &bulkErr):
+		if len(bulkErr.WriteErrors) > 0 {
+			return bulkErr.WriteErrors[0].Code
+		}
+		if bulkErr.WriteConcernError != nil {
+			return bulkErr.WriteConcernError.Code
+		}
+	}
+
+	return 0
+}
+
+// WriteErrors returns the failed writes of an operation, or nil if the error is not a failed write
+func WriteErrors(err error) []WriteError {
+	var (
+		writeErr mongo.WriteException
+		bulkErr  mongo.BulkWriteException
+	)
+
+	var writeErrors []WriteError
+	switch {
+	case errors.As(err, &writeErr):
+		for _, we := range writeErr.WriteErrors {
+			writeErrors = append(writeErrors, newWriteError(we))
+		}
+	case errors.As(err, &bulkErr):
+		for _, we := range bulkErr.WriteErrors {
+			writeErrors = append(writeErrors, newWriteError(we.WriteError))
+		}
+	}
+
+	return writeErrors
+}
+
+func newWriteError(we mongo.WriteError) WriteError {
+	e := WriteError{Index: we.Index, Code: we.Code, Message: we.Message}
+	if we.Raw != nil {
+		if v, err := we.Raw.LookupErr("keyPattern"); err == nil {
+			e.KeyPattern, _ = v.DocumentOK()
+		}
+		if v, err := we.Raw.LookupErr("keyValue"); err == nil {
+			e.KeyValue, _ = v.DocumentOK()
+		}
+	}
+
+	return e
+}
+
+// IsDuplicateKey returns true if the error is, or includes, a duplicate key violation
+func IsDuplicateKey(err error) bool {
+	return mongo.IsDuplicateKeyError(err)
+}
+
+// IsNetworkError returns true if the error is a network error
+func IsNetworkError(err error) bool {
+	return mongo.IsNetworkError(err)
+}
+
+// IsWriteConflict returns true if the error is a write conflict with another operation, e.g. in a transaction
+func IsWriteConflict(err error) bool {
+	return hasErrorCode(err, codeWriteConflict)
+}
+
+// IsNotPrimary returns true if the error is due to the server not being (or no longer being) the primary, e.g. as the
+// primary stepped down during an election
+func IsNotPrimary(err error) bool {
+	return hasErrorCode(err, codeNotWritablePrimary, codeNotPrimaryNoSecondaryOk, codeNotPrimaryOrSecondary, codePrimarySteppedDown)
+}
+
+// IsValidationFailure returns true if the error is due to a document failing the collection's schema validation
+func IsValidationFailure(err error) bool {
+	return hasErrorCode(err, codeDocumentValidationFailure)
+}
+
+// IsRetryable returns true if the error is transient, so an operation may succeed if retried: a network error, or a
+// server error labelled RetryableWriteError or with a code indicating a change in the replica set's state
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) && labeled.HasErrorLabel(retryableWriteErrorLabel) {
+		return true
+	}
+
+	return hasErrorCode(err, retryableCodes...)
+}
+
+// hasErrorCode returns true if the error is a server error with any of the given codes
+func hasErrorCode(err error, codes ...int) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func IsTimeout(err error) bool {
-	e, ok := err.(Error)
-	return ok && mongo.IsTimeout(e.inner)
+	var e Error
+	return errors.As(err, &e) && mongo.IsTimeout(e.inner)
 }
 
 func IsServerErr(err error) bool {
-	e, ok := err.(Error)
-	return ok && !mongo.IsTimeout(e.inner)
+	var e Error
+	return errors.As(err, &e) && !mongo.IsTimeout(e.inner)
+}
+
+// WrapError wraps an error as the errors returned by Collection operations are wrapped, so that the predicates such as
+// IsServerErr and IsTimeout classify the errors of alternative implementations of CollectionAPI, such as the in-memory
+// fake in the mongotest package, as they would those of a Collection. An error that is already wrapped is unchanged
+func WrapError(err error) error {
+	var e Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	return wrapMongoError(err)
 }
 
 func wrapMongoError(err error) error {
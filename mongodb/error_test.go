@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorPredicates(t *testing.T) {
+	Convey("Given errors returned by operations", t, func() {
+		duplicate := Error{inner: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}}
+		network := Error{inner: mongo.CommandError{Labels: []string{"NetworkError"}}}
+		writeConflict := Error{inner: mongo.CommandError{Code: 112, Name: "WriteConflict"}}
+		notPrimary := Error{inner: mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}}
+		validation := Error{inner: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}}}}
+
+		Convey("Then each error is classified by its predicate", func() {
+			So(IsDuplicateKey(duplicate), ShouldBeTrue)
+			So(IsNetworkError(network), ShouldBeTrue)
+			So(IsWriteConflict(writeConflict), ShouldBeTrue)
+			So(IsNotPrimary(notPrimary), ShouldBeTrue)
+			So(IsNotPrimary(Error{inner: mongo.CommandError{Code: 189}}), ShouldBeTrue)
+			So(IsValidationFailure(validation), ShouldBeTrue)
+		})
+
+		Convey("Then errors are not classified by the predicates of other errors", func() {
+			So(IsDuplicateKey(validation), ShouldBeFalse)
+			So(IsNetworkError(notPrimary), ShouldBeFalse)
+			So(IsWriteConflict(duplicate), ShouldBeFalse)
+			So(IsNotPrimary(writeConflict), ShouldBeFalse)
+			So(IsValidationFailure(duplicate), ShouldBeFalse)
+			So(IsRetryable(duplicate), ShouldBeFalse)
+		})
+
+		Convey("Then the predicates see through further wrapping", func() {
+			So(IsDuplicateKey(fmt.Errorf("failed to create dataset: %w", duplicate)), ShouldBeTrue)
+			So(IsNotPrimary(fmt.Errorf("failed to update dataset: %w", notPrimary)), ShouldBeTrue)
+		})
+
+		Convey("Then IsTimeout and IsServerErr classify errors, including wrapped errors", func() {
+			timeout := Error{inner: context.DeadlineExceeded}
+			So(IsTimeout(timeout), ShouldBeTrue)
+			So(IsTimeout(fmt.Errorf("failed to find datasets: %w", timeout)), ShouldBeTrue)
+			So(IsTimeout(writeConflict), ShouldBeFalse)
+			So(IsServerErr(fmt.Errorf("failed to update dataset: %w", writeConflict)), ShouldBeTrue)
+			So(IsServerErr(fmt.Errorf("failed to find datasets: %w", timeout)), ShouldBeFalse)
+			So(IsServerErr(errors.New("not a mongodb error")), ShouldBeFalse)
+		})
+
+		Convey("Then WrapError wraps an error as a Collection does, and leaves a wrapped error unchanged", func() {
+			So(WrapError(nil), ShouldBeNil)
+			So(WrapError(mongo.ErrNoDocuments), ShouldEqual, ErrNoDocumentFound)
+			So(WrapError(context.DeadlineExceeded), ShouldResemble, Error{inner: context.DeadlineExceeded})
+			So(WrapError(writeConflict), ShouldResemble, writeConflict)
+		})
+
+		Convey("Then no predicate classifies a nil error or ErrNoDocumentFound", func() {
+			for _, err := range []error{nil, ErrNoDocumentFound} {
+				So(IsDuplicateKey(err), ShouldBeFalse)
+				So(IsNetworkError(err), ShouldBeFalse)
+				So(IsWriteConflict(err), ShouldBeFalse)
+				So(IsNotPrimary(err), ShouldBeFalse)
+				So(IsValidationFailure(err), ShouldBeFalse)
+			}
+		})
+	})
+}
+
+func TestErrorCode(t *testing.T) {
+	Convey("Given server errors", t, func() {
+		Convey("Then the code of a command error is returned", func() {
+			err := Error{inner: mongo.CommandError{Code: 112}}
+			So(ErrorCode(err), ShouldEqual, 112)
+			So(err.Code(), ShouldEqual, 112)
+		})
+
+		Convey("Then the code of the first write error is returned for a failed write", func() {
+			So(ErrorCode(mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121}, {Code: 11000}}}), ShouldEqual, 121)
+			So(ErrorCode(mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Code: 11000}}}}), ShouldEqual, 11000)
+		})
+
+		Convey("Then the code of the write concern error is returned if no write failed", func() {
+			So(ErrorCode(mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}), ShouldEqual, 64)
+		})
+
+		Convey("Then 0 is returned for other errors", func() {
+			So(ErrorCode(nil), ShouldEqual, 0)
+			So(ErrorCode(ErrNoDocumentFound), ShouldEqual, 0)
+			So(ErrorCode(errors.New("decode failure")), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestWriteErrors(t *testing.T) {
+	Convey("Given an InsertMany error with a duplicate key violation", t, func() {
+		raw, err := bson.Marshal(bson.D{
+			{Key: "code", Value: 11000},
+			{Key: "keyPattern", Value: bson.D{{Key: "name", Value: 1}}},
+			{Key: "keyValue", Value: bson.D{{Key: "name", Value: "cpih"}}},
+		})
+		So(err, ShouldBeNil)
+
+		insertErr := Error{inner: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+			{WriteError: mongo.WriteError{Index: 2, Code: 11000, Message: "E11000 duplicate key error", Raw: raw}},
+			{WriteError: mongo.WriteError{Index: 4, Code: 121, Message: "Document failed validation"}},
+		}}}
+
+		Convey("Then WriteErrors returns each failed write with its index, code, and key pattern and value", func() {
+			writeErrors := WriteErrors(insertErr)
+			So(writeErrors, ShouldHaveLength, 2)
+			So(writeErrors[0].Index, ShouldEqual, 2)
+			So(writeErrors[0].Code, ShouldEqual, 11000)
+			So(writeErrors[0].IsDuplicateKey(), ShouldBeTrue)
+			So(writeErrors[0].Error(), ShouldEqual, "E11000 duplicate key error")
+			So(writeErrors[0].KeyPattern.Lookup("name").Int32(), ShouldEqual, 1)
+			So(writeErrors[0].KeyValue.Lookup("name").StringValue(), ShouldEqual, "cpih")
+			So(writeErrors[1], ShouldResemble, WriteError{Index: 4, Code: 121, Message: "Document failed validation"})
+		})
+
+		Convey("Then errors.As extracts the first failed write", func() {
+			var writeErr WriteError
+			So(errors.As(fmt.Errorf("failed to insert: %w", insertErr), &writeErr), ShouldBeTrue)
+			So(writeErr.Index, ShouldEqual, 2)
+			So(writeErr.KeyValue.Lookup("name").StringValue(), ShouldEqual, "cpih")
+		})
+
+		Convey("Then errors.As still extracts the driver error", func() {
+			var bulkErr mongo.BulkWriteException
+			So(errors.As(insertErr, &bulkErr), ShouldBeTrue)
+			So(bulkErr.WriteErrors, ShouldHaveLength, 2)
+		})
+	})
+
+	Convey("Given an error that is not a failed write", t, func() {
+		err := Error{inner: mongo.CommandError{Code: 112}}
+
+		Convey("Then WriteErrors returns nil, and errors.As does not extract a WriteError", func() {
+			var writeErr WriteError
+			So(WriteErrors(err), ShouldBeNil)
+			So(errors.As(err, &writeErr), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,217 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Stages of a query plan
+const (
+	StageCollectionScan = "COLLSCAN"
+	StageIndexScan      = "IXSCAN"
+	StageFetch          = "FETCH"
+	StageSort           = "SORT"
+	StageCountScan      = "COUNT_SCAN"
+)
+
+// ErrNoQueryPlan is returned by the Explain methods when the server's explain output contains no query plan, e.g. for
+// an aggregation pipeline that does not start by reading from the collection
+var ErrNoQueryPlan = errors.New("explain output contains no query plan")
+
+// ExplainPlan is a summary of the plan chosen by the server to execute a query, and the work done executing it
+type ExplainPlan struct {
+	WinningStage string   // The root stage of the winning plan, e.g. "FETCH".
+	Stages       []string // All the stages of the winning plan, from the root to the leaves, e.g. ["FETCH", "IXSCAN"].
+	IndexNames   []string // The names of the indexes used by the winning plan, if any.
+	KeysExamined int      // The number of index keys examined.
+	DocsExamined int      // The number of documents examined.
+	DocsReturned int      // The number of documents returned.
+	Raw          bson.Raw // The complete explain output.
+}
+
+// IndexName returns the name of the (first) index used by the winning plan, or "" if no index is used
+func (p *ExplainPlan) IndexName() string {
+	if len(p.IndexNames) == 0 {
+		return ""
+	}
+
+	return p.IndexNames[0]
+}
+
+// HasStage returns true if the winning plan includes the given stage
+func (p *ExplainPlan) HasStage(stage string) bool {
+	for _, s := range p.Stages {
+		if s == stage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCollectionScan returns true if the winning plan scans the whole collection, rather than using an index
+func (p *ExplainPlan) IsCollectionScan() bool {
+	return p.HasStage(StageCollectionScan)
+}
+
+// explainStage is a stage of a query plan in the server's explain output
+type explainStage struct {
+	Stage       string         `bson:"stage"`
+	IndexName   string         `bson:"indexName"`
+	InputStage  *explainStage  `bson:"inputStage"`
+	InputStages []explainStage `bson:"inputStages"`
+	QueryPlan   *explainStage  `bson:"queryPlan"` // Set instead of Stage when the slot based execution engine is used
+}
+
+// explainOutput is the server's output for the explain command, at executionStats verbosity
+type explainOutput struct {
+	QueryPlanner *struct {
+		WinningPlan explainStage `bson:"winningPlan"`
+	} `bson:"queryPlanner"`
+	ExecutionStats struct {
+		NReturned         int64 `bson:"nReturned"`
+		TotalKeysExamined int64 `bson:"totalKeysExamined"`
+		TotalDocsExamined int64 `bson:"totalDocsExamined"`
+	} `bson:"executionStats"`
+	Stages []struct {
+		Cursor *explainOutput `bson:"$cursor"`
+	} `bson:"stages"` // Set for an aggregation pipeline that is not executed entirely by the query planner
+}
+
+// ExplainFind returns the plan the server would use to execute Find with the same filter and options, having executed
+// it to gather the execution statistics
+func (c *Collection) ExplainFind(ctx context.Context, filter interface{}, opts ...FindOption) (_ *ExplainPlan, err error) {
+	ctx, op := c.startOperation(ctx, "ExplainFind")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	fo := newFindOptions(opts...)
+	if fo.sort == nil {
+		fo.sort = bson.M{"_id": 1}
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	command := bson.D{{Key: "find", Value: c.collection.Name()}, {Key: "filter", Value: filter}, {Key: "sort", Value: fo.sort}}
+	if fo.skip > 0 {
+		command = append(command, bson.E{Key: "skip", Value: fo.skip})
+	}
+	if fo.limit > 0 {
+		command = append(command, bson.E{Key: "limit", Value: fo.limit})
+	}
+	if fo.projection != nil {
+		command = append(command, bson.E{Key: "projection", Value: fo.projection})
+	}
+
+	return c.explain(ctx, op, command)
+}
+
+// ExplainCount returns the plan the server would use to execute Count with the same filter and options, having
+// executed it to gather the execution statistics
+func (c *Collection) ExplainCount(ctx context.Context, filter interface{}, opts ...FindOption) (_ *ExplainPlan, err error) {
+	ctx, op := c.startOperation(ctx, "ExplainCount")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	// Count is executed by the driver as an aggregation
+	fo := newFindOptions(opts...)
+	pipeline := bson.A{bson.M{"$match": filter}}
+	if fo.skip > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": fo.skip})
+	}
+	if fo.limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": fo.limit})
+	}
+	pipeline = append(pipeline, bson.M{"$group": bson.M{"_id": 1, "n": bson.M{"$sum": 1}}})
+
+	return c.explain(ctx, op, bson.D{{Key: "aggregate", Value: c.collection.Name()}, {Key: "pipeline", Value: pipeline}, {Key: "cursor", Value: bson.D{}}})
+}
+
+// ExplainAggregate returns the plan the server would use to execute Aggregate with the same pipeline, having executed
+// it to gather the execution statistics. The plan is that of the initial stages of the pipeline which read from the
+// collection; if no stages do so, ErrNoQueryPlan is returned
+func (c *Collection) ExplainAggregate(ctx context.Context, pipeline interface{}) (_ *ExplainPlan, err error) {
+	ctx, op := c.startOperation(ctx, "ExplainAggregate")
+	defer op.end(&err)
+	op.filter(pipeline)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	return c.explain(ctx, op, bson.D{{Key: "aggregate", Value: c.collection.Name()}, {Key: "pipeline", Value: pipeline}, {Key: "cursor", Value: bson.D{}}})
+}
+
+func (c *Collection) explain(ctx context.Context, op *operation, command bson.D) (*ExplainPlan, error) {
+	// The explain command executes the query, but not any writes of an aggregation pipeline
+	var raw bson.Raw
+	err := op.retry(ctx, true, func() (err error) {
+		raw, err = c.collection.Database().RunCommand(ctx, bson.D{
+			{Key: "explain", Value: command},
+			{Key: "verbosity", Value: "executionStats"},
+		}).Raw()
+		return err
+	})
+	if err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	return parseExplain(raw)
+}
+
+// parseExplain returns a summary of the explain output
+func parseExplain(raw bson.Raw) (*ExplainPlan, error) {
+	var output explainOutput
+	if err := bson.Unmarshal(raw, &output); err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	if output.QueryPlanner == nil && len(output.Stages) > 0 && output.Stages[0].Cursor != nil {
+		output = *output.Stages[0].Cursor
+	}
+	if output.QueryPlanner == nil {
+		return nil, ErrNoQueryPlan
+	}
+
+	plan := &ExplainPlan{
+		KeysExamined: int(output.ExecutionStats.TotalKeysExamined),
+		DocsExamined: int(output.ExecutionStats.TotalDocsExamined),
+		DocsReturned: int(output.ExecutionStats.NReturned),
+		Raw:          raw,
+	}
+	plan.addStages(&output.QueryPlanner.WinningPlan)
+	if len(plan.Stages) > 0 {
+		plan.WinningStage = plan.Stages[0]
+	}
+
+	return plan, nil
+}
+
+// addStages adds the given stage, and its input stages, to the plan
+func (p *ExplainPlan) addStages(s *explainStage) {
+	if s.QueryPlan != nil {
+		s = s.QueryPlan
+	}
+
+	if s.Stage != "" {
+		p.Stages = append(p.Stages, s.Stage)
+	}
+	if s.IndexName != "" {
+		p.IndexNames = append(p.IndexNames, s.IndexName)
+	}
+
+	if s.InputStage != nil {
+		p.addStages(s.InputStage)
+	}
+	for i := range s.InputStages {
+		p.addStages(&s.InputStages[i])
+	}
+}
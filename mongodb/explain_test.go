@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseExplain(t *testing.T) {
+	Convey("Given the explain output of a find using an index", t, func() {
+		raw := marshalExplain(bson.M{
+			"queryPlanner": bson.M{"winningPlan": bson.M{
+				"stage":      "FETCH",
+				"inputStage": bson.M{"stage": "IXSCAN", "indexName": "state_1", "keyPattern": bson.M{"state": 1}},
+			}},
+			"executionStats": bson.M{"nReturned": int32(2), "totalKeysExamined": int32(2), "totalDocsExamined": int32(2)},
+		})
+
+		Convey("Then the plan summary reports the stages, index and execution statistics", func() {
+			plan, err := parseExplain(raw)
+			So(err, ShouldBeNil)
+			So(plan.WinningStage, ShouldEqual, StageFetch)
+			So(plan.Stages, ShouldResemble, []string{StageFetch, StageIndexScan})
+			So(plan.IndexName(), ShouldEqual, "state_1")
+			So(plan.IsCollectionScan(), ShouldBeFalse)
+			So(plan.KeysExamined, ShouldEqual, 2)
+			So(plan.DocsExamined, ShouldEqual, 2)
+			So(plan.DocsReturned, ShouldEqual, 2)
+			So(plan.Raw, ShouldResemble, raw)
+		})
+	})
+
+	Convey("Given the explain output of a find, executed by the slot based engine, scanning the collection", t, func() {
+		raw := marshalExplain(bson.M{
+			"queryPlanner": bson.M{"winningPlan": bson.M{
+				"queryPlan":     bson.M{"stage": "SORT", "inputStage": bson.M{"stage": "COLLSCAN"}},
+				"slotBasedPlan": bson.M{"stages": "..."},
+			}},
+			"executionStats": bson.M{"nReturned": int64(1), "totalKeysExamined": int64(0), "totalDocsExamined": int64(100)},
+		})
+
+		Convey("Then the plan is reported as a collection scan", func() {
+			plan, err := parseExplain(raw)
+			So(err, ShouldBeNil)
+			So(plan.Stages, ShouldResemble, []string{StageSort, StageCollectionScan})
+			So(plan.IsCollectionScan(), ShouldBeTrue)
+			So(plan.IndexName(), ShouldBeEmpty)
+			So(plan.DocsExamined, ShouldEqual, 100)
+		})
+	})
+
+	Convey("Given the explain output of an aggregation with a query plan for its initial stages", t, func() {
+		raw := marshalExplain(bson.M{
+			"stages": bson.A{
+				bson.M{"$cursor": bson.M{
+					"queryPlanner": bson.M{"winningPlan": bson.M{
+						"stage": "OR",
+						"inputStages": bson.A{
+							bson.M{"stage": "IXSCAN", "indexName": "a_1"},
+							bson.M{"stage": "IXSCAN", "indexName": "b_1"},
+						},
+					}},
+					"executionStats": bson.M{"nReturned": int32(3)},
+				}},
+				bson.M{"$group": bson.M{}},
+			},
+		})
+
+		Convey("Then the plan of the initial stages is reported, with all the indexes used", func() {
+			plan, err := parseExplain(raw)
+			So(err, ShouldBeNil)
+			So(plan.WinningStage, ShouldEqual, "OR")
+			So(plan.IndexNames, ShouldResemble, []string{"a_1", "b_1"})
+			So(plan.DocsReturned, ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given explain output with no query plan", t, func() {
+		raw := marshalExplain(bson.M{"stages": bson.A{bson.M{"$documents": bson.A{}}}})
+
+		Convey("Then an ErrNoQueryPlan error is returned", func() {
+			_, err := parseExplain(raw)
+			So(err, ShouldEqual, ErrNoQueryPlan)
+		})
+	})
+}
+
+func marshalExplain(output bson.M) bson.Raw {
+	b, err := bson.Marshal(output)
+	So(err, ShouldBeNil)
+	return b
+}
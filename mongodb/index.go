@@ -0,0 +1,371 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// idIndexName is the name of the index MongoDB maintains on _id, which is never reported as undeclared or dropped
+const idIndexName = "_id_"
+
+// ErrIndexConflict is returned by EnsureIndexes when an existing index has the same name as a declared index, but a
+// different definition
+var ErrIndexConflict = errors.New("existing index conflicts with declared index")
+
+// IndexSpec declares an index on a collection
+type IndexSpec struct {
+	Name          string             // The index name; if empty the MongoDB default name (e.g. "state_1_id_-1") is used
+	Keys          bson.D             // The ordered index keys, e.g. bson.D{{Key: "state", Value: 1}}
+	Unique        bool               // Whether the index enforces uniqueness of the keys
+	Sparse        bool               // Whether the index only references documents containing the keys
+	ExpireAfter   *time.Duration     // The TTL of documents, for a TTL index, or nil
+	PartialFilter interface{}        // The filter for a partial index, or nil
+	Collation     *options.Collation // The collation of the index, or nil
+}
+
+// EnsureIndexesResult is the result type returned from EnsureIndexes operations.
+type EnsureIndexesResult struct {
+	Created    []string // The names of the declared indexes that were created.
+	Undeclared []string // The names of the existing indexes that are not declared (excluding the _id index).
+	Dropped    []string // The names of the undeclared indexes that were dropped.
+}
+
+type IndexOption func(*indexOptions)
+
+var (
+	DropUndeclaredIndexes = func() IndexOption { return func(i *indexOptions) { i.dropUndeclared = true } }
+)
+
+type indexOptions struct {
+	dropUndeclared bool
+}
+
+func newIndexOptions(opts ...IndexOption) *indexOptions {
+	i := &indexOptions{}
+	for _, o := range opts {
+		o(i)
+	}
+
+	return i
+}
+
+// indexDocument is the index description returned by the listIndexes command
+type indexDocument struct {
+	Name               string          `bson:"name"`
+	Key                bson.D          `bson:"key"`
+	Unique             bool            `bson:"unique,omitempty"`
+	Sparse             bool            `bson:"sparse,omitempty"`
+	ExpireAfterSeconds *int64          `bson:"expireAfterSeconds,omitempty"`
+	PartialFilter      bson.Raw        `bson:"partialFilterExpression,omitempty"`
+	Collation          *indexCollation `bson:"collation,omitempty"`
+}
+
+// indexCollation is the collation of an index returned by the listIndexes command
+type indexCollation struct {
+	Locale          string `bson:"locale"`
+	CaseLevel       bool   `bson:"caseLevel"`
+	CaseFirst       string `bson:"caseFirst"`
+	Strength        int    `bson:"strength"`
+	NumericOrdering bool   `bson:"numericOrdering"`
+	Alternate       string `bson:"alternate"`
+	MaxVariable     string `bson:"maxVariable"`
+	Normalization   bool   `bson:"normalization"`
+	Backwards       bool   `bson:"backwards"`
+}
+
+// ListIndexes returns the specifications of the indexes that exist on the collection
+func (c *Collection) ListIndexes(ctx context.Context) (_ []IndexSpec, err error) {
+	ctx, op := c.startOperation(ctx, "ListIndexes")
+	defer op.end(&err)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	return c.listIndexes(ctx, op)
+}
+
+// EnsureIndexes creates the declared indexes that do not yet exist on the collection, and reports the existing indexes
+// that are no longer declared. If the DropUndeclaredIndexes option is given, those undeclared indexes are also dropped
+// An index is identified by its name. If an existing index has the name of a declared index but a different keys,
+// uniqueness, sparseness, TTL, partial filter or collation, an ErrIndexConflict error is returned, and no indexes are created or dropped
+func (c *Collection) EnsureIndexes(ctx context.Context, specs []IndexSpec, opts ...IndexOption) (_ *EnsureIndexesResult, err error) {
+	ctx, op := c.startOperation(ctx, "EnsureIndexes")
+	defer op.end(&err)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	io := newIndexOptions(opts...)
+
+	existing, err := c.listIndexes(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	existingByName := make(map[string]IndexSpec, len(existing))
+	for _, spec := range existing {
+		existingByName[spec.Name] = spec
+	}
+
+	result := &EnsureIndexesResult{}
+	declared := make(map[string]bool, len(specs))
+	var models []mongo.IndexModel
+	for _, spec := range specs {
+		name := spec.IndexName()
+		declared[name] = true
+
+		e, ok := existingByName[name]
+		if !ok {
+			models = append(models, spec.asDriverIndexModel())
+			result.Created = append(result.Created, name)
+			continue
+		}
+		if !e.Matches(spec) {
+			return nil, fmt.Errorf("%w: %s", ErrIndexConflict, name)
+		}
+	}
+
+	for _, spec := range existing {
+		if spec.Name != idIndexName && !declared[spec.Name] {
+			result.Undeclared = append(result.Undeclared, spec.Name)
+		}
+	}
+
+	if len(models) > 0 {
+		// Creating an index that already exists with the same specification has no effect, so can be retried
+		err = op.retry(ctx, true, func() error {
+			_, err := c.collection.Indexes().CreateMany(ctx, models)
+			return err
+		})
+		if err != nil {
+			return nil, wrapMongoError(err)
+		}
+	}
+
+	if io.dropUndeclared {
+		for _, name := range result.Undeclared {
+			err = op.retry(ctx, false, func() error {
+				_, err := c.collection.Indexes().DropOne(ctx, name)
+				return err
+			})
+			if err != nil {
+				return result, wrapMongoError(err)
+			}
+			result.Dropped = append(result.Dropped, name)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Collection) listIndexes(ctx context.Context, op *operation) ([]IndexSpec, error) {
+	var docs []indexDocument
+	err := op.retry(ctx, true, func() error {
+		cursor, err := c.collection.Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &docs)
+	})
+	if err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	specs := make([]IndexSpec, len(docs))
+	for i, d := range docs {
+		specs[i] = IndexSpec{
+			Name:   d.Name,
+			Keys:   d.Key,
+			Unique: d.Unique,
+			Sparse: d.Sparse,
+		}
+		if d.ExpireAfterSeconds != nil {
+			ttl := time.Duration(*d.ExpireAfterSeconds) * time.Second
+			specs[i].ExpireAfter = &ttl
+		}
+		if d.PartialFilter != nil {
+			specs[i].PartialFilter = d.PartialFilter
+		}
+		if d.Collation != nil {
+			collation := options.Collation(*d.Collation)
+			specs[i].Collation = &collation
+		}
+	}
+
+	return specs, nil
+}
+
+// IndexName returns the name of the index, defaulting to the name MongoDB generates from the keys
+func (spec IndexSpec) IndexName() string {
+	if spec.Name != "" {
+		return spec.Name
+	}
+
+	parts := make([]string, 0, len(spec.Keys))
+	for _, k := range spec.Keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", k.Key, k.Value))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+func (spec IndexSpec) asDriverIndexModel() mongo.IndexModel {
+	io := options.Index().SetName(spec.IndexName())
+	if spec.Unique {
+		io.SetUnique(true)
+	}
+	if spec.Sparse {
+		io.SetSparse(true)
+	}
+	if spec.ExpireAfter != nil {
+		io.SetExpireAfterSeconds(int32(spec.ExpireAfter.Seconds()))
+	}
+	if spec.PartialFilter != nil {
+		io.SetPartialFilterExpression(spec.PartialFilter)
+	}
+	if spec.Collation != nil {
+		io.SetCollation(spec.Collation)
+	}
+
+	return mongo.IndexModel{Keys: spec.Keys, Options: io}
+}
+
+// Matches reports whether the existing index spec has the same keys, uniqueness, sparseness, TTL, partial filter and
+// collation as the declared index spec
+func (spec IndexSpec) Matches(declared IndexSpec) bool {
+	if spec.Unique != declared.Unique || spec.Sparse != declared.Sparse || len(spec.Keys) != len(declared.Keys) {
+		return false
+	}
+
+	if (spec.ExpireAfter == nil) != (declared.ExpireAfter == nil) ||
+		(spec.ExpireAfter != nil && spec.ExpireAfter.Truncate(time.Second) != declared.ExpireAfter.Truncate(time.Second)) {
+		return false
+	}
+
+	for i, k := range spec.Keys {
+		if k.Key != declared.Keys[i].Key || !indexKeyValuesEqual(k.Value, declared.Keys[i].Value) {
+			return false
+		}
+	}
+
+	return partialFiltersEqual(spec.PartialFilter, declared.PartialFilter) &&
+		collationWithDefaults(spec.Collation) == collationWithDefaults(declared.Collation)
+}
+
+// partialFiltersEqual compares partial filters as canonical BSON, so that the same filter given as a bson.M, bson.D,
+// struct or bson.Raw is equal. A filter that cannot be marshalled is never equal
+func partialFiltersEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	ca, err := canonicalDocument(a)
+	if err != nil {
+		return false
+	}
+	cb, err := canonicalDocument(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(ca, cb)
+}
+
+// canonicalDocument returns the document as a bson.D with the keys of it and its embedded documents sorted, and its
+// integral numbers as int64, as the server may report a filter with a different key order or numeric types
+func canonicalDocument(doc interface{}) (bson.D, error) {
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var d bson.D
+	if err = bson.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+
+	return canonicalValue(d).(bson.D), nil
+}
+
+func canonicalValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bson.D:
+		d := make(bson.D, len(t))
+		for i, e := range t {
+			d[i] = bson.E{Key: e.Key, Value: canonicalValue(e.Value)}
+		}
+		sort.Slice(d, func(i, j int) bool { return d[i].Key < d[j].Key })
+		return d
+	case bson.A:
+		a := make(bson.A, len(t))
+		for i, e := range t {
+			a[i] = canonicalValue(e)
+		}
+		return a
+	case int32:
+		return int64(t)
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	}
+
+	return v
+}
+
+// collationWithDefaults returns the collation with the server's defaults in place of its unset fields, as the server
+// reports the collation of an index with every field set, so that collations can be compared field by field. A nil
+// collation is equivalent to the simple locale
+func collationWithDefaults(c *options.Collation) options.Collation {
+	if c == nil || c.Locale == "" || c.Locale == "simple" {
+		return options.Collation{Locale: "simple"}
+	}
+
+	d := *c
+	if d.Strength == 0 {
+		d.Strength = 3
+	}
+	if d.CaseFirst == "" {
+		d.CaseFirst = "off"
+	}
+	if d.Alternate == "" {
+		d.Alternate = "non-ignorable"
+	}
+	if d.MaxVariable == "" {
+		d.MaxVariable = "punct"
+	}
+
+	return d
+}
+
+// indexKeyValuesEqual compares index key values, treating all numeric types as equal if they have the same value
+func indexKeyValuesEqual(a, b interface{}) bool {
+	af, aNumeric := asFloat(a)
+	bf, bNumeric := asFloat(b)
+	if aNumeric || bNumeric {
+		return aNumeric && bNumeric && af == bf
+	}
+
+	return a == b
+}
+
+func asFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIndexSpec(t *testing.T) {
+	Convey("IndexName returns the given name of an index", t, func() {
+		spec := IndexSpec{Name: "my_index", Keys: bson.D{{Key: "state", Value: 1}}}
+		So(spec.IndexName(), ShouldEqual, "my_index")
+	})
+
+	Convey("IndexName defaults to the name MongoDB generates from the keys", t, func() {
+		spec := IndexSpec{Keys: bson.D{{Key: "state", Value: 1}, {Key: "last_updated", Value: -1}, {Key: "text", Value: "text"}}}
+		So(spec.IndexName(), ShouldEqual, "state_1_last_updated_-1_text_text")
+	})
+
+	Convey("Given an existing index spec", t, func() {
+		ttl := time.Hour
+		existing := IndexSpec{Name: "state_1", Keys: bson.D{{Key: "state", Value: int32(1)}}, Unique: true, ExpireAfter: &ttl}
+
+		Convey("Matches is true for a declared spec with equivalent keys, uniqueness and TTL", func() {
+			declaredTTL := time.Hour
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Unique: true, ExpireAfter: &declaredTTL}), ShouldBeTrue)
+		})
+
+		Convey("Matches is false for a declared spec with different keys", func() {
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: -1}}, Unique: true, ExpireAfter: &ttl}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: "text"}}, Unique: true, ExpireAfter: &ttl}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}}, Unique: true, ExpireAfter: &ttl}), ShouldBeFalse)
+		})
+
+		Convey("Matches is false for a declared spec with different uniqueness or TTL", func() {
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, ExpireAfter: &ttl}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Unique: true}), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an existing partial index spec, with the filter reported by the server", t, func() {
+		filter, err := bson.Marshal(bson.D{{Key: "state", Value: "published"}, {Key: "count", Value: bson.D{{Key: "$gt", Value: int32(1)}}}})
+		So(err, ShouldBeNil)
+		existing := IndexSpec{Name: "state_1", Keys: bson.D{{Key: "state", Value: int32(1)}}, PartialFilter: bson.Raw(filter)}
+
+		Convey("Matches is true for a declared spec with the same filter, in any key order and numeric type", func() {
+			declared := IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, PartialFilter: bson.M{"count": bson.M{"$gt": 1.0}, "state": "published"}}
+			So(existing.Matches(declared), ShouldBeTrue)
+		})
+
+		Convey("Matches is false for a declared spec with a different filter, or none", func() {
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, PartialFilter: bson.M{"state": "published"}}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, PartialFilter: bson.M{"count": bson.M{"$gt": 2}, "state": "published"}}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}}), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an existing index spec with a collation, with every field reported by the server", t, func() {
+		existing := IndexSpec{Name: "state_1", Keys: bson.D{{Key: "state", Value: int32(1)}}, Collation: &options.Collation{
+			Locale: "en", Strength: 2, CaseFirst: "off", Alternate: "non-ignorable", MaxVariable: "punct",
+		}}
+
+		Convey("Matches is true for a declared spec with the same collation, with unset fields taking their defaults", func() {
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Collation: &options.Collation{Locale: "en", Strength: 2}}), ShouldBeTrue)
+		})
+
+		Convey("Matches is false for a declared spec with a different collation, or none", func() {
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Collation: &options.Collation{Locale: "en"}}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Collation: &options.Collation{Locale: "fr", Strength: 2}}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Collation: &options.Collation{Locale: "en", Strength: 2, NumericOrdering: true}}), ShouldBeFalse)
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}}), ShouldBeFalse)
+		})
+
+		Convey("Matches treats no collation as the simple locale", func() {
+			existing.Collation = nil
+			So(existing.Matches(IndexSpec{Keys: bson.D{{Key: "state", Value: 1}}, Collation: &options.Collation{Locale: "simple"}}), ShouldBeTrue)
+		})
+	})
+}
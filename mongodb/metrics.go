@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Names of the metrics recorded for each Collection operation
+const (
+	OperationDurationMetric = "db.client.operation.duration"
+	OperationErrorsMetric   = "db.client.operation.errors"
+	DocumentsReturnedMetric = "db.client.documents.returned"
+	DocumentsModifiedMetric = "db.client.documents.modified"
+	OperationRetriesMetric  = "db.client.operation.retries"
+)
+
+// Classes of error, recorded in the error.type attribute of the operation errors metric
+const (
+	ErrorClassTimeout  = "timeout"
+	ErrorClassServer   = "server"
+	ErrorClassNotFound = "not_found"
+	ErrorClassOther    = "other"
+)
+
+// Attribute keys of the recorded metrics
+const (
+	dbNamespaceKey  = attribute.Key("db.namespace")
+	dbCollectionKey = attribute.Key("db.collection.name")
+	dbOperationKey  = attribute.Key("db.operation.name")
+	errorTypeKey    = attribute.Key("error.type")
+)
+
+type instruments struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	returned metric.Int64Counter
+	modified metric.Int64Counter
+	retries  metric.Int64Counter
+}
+
+var (
+	metricsOnce    sync.Once
+	operationMeter instruments
+)
+
+// getInstruments returns the metric instruments, creating them from the global meter provider on first use
+// Instruments created before a meter provider is set delegate to it once it is set
+func getInstruments() instruments {
+	metricsOnce.Do(func() {
+		meter := otel.GetMeterProvider().Meter("dp-mongodb")
+		operationMeter.duration, _ = meter.Float64Histogram(OperationDurationMetric,
+			metric.WithDescription("Duration of Collection operations"), metric.WithUnit("s"))
+		operationMeter.errors, _ = meter.Int64Counter(OperationErrorsMetric,
+			metric.WithDescription("Number of failed Collection operations, by class of error"), metric.WithUnit("{error}"))
+		operationMeter.returned, _ = meter.Int64Counter(DocumentsReturnedMetric,
+			metric.WithDescription("Number of documents returned by Collection operations"), metric.WithUnit("{document}"))
+		operationMeter.modified, _ = meter.Int64Counter(DocumentsModifiedMetric,
+			metric.WithDescription("Number of documents inserted, modified or deleted by Collection operations"), metric.WithUnit("{document}"))
+		operationMeter.retries, _ = meter.Int64Counter(OperationRetriesMetric,
+			metric.WithDescription("Number of retries of Collection operations after a transient error, by class of error"), metric.WithUnit("{retry}"))
+	})
+
+	return operationMeter
+}
+
+// errorClass classifies an error returned by a Collection operation
+func errorClass(err error) string {
+	var serverErr mongo.ServerError
+	switch {
+	case errors.Is(err, ErrNoDocumentFound):
+		return ErrorClassNotFound
+	case errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err):
+		return ErrorClassTimeout
+	case errors.As(err, &serverErr):
+		return ErrorClassServer
+	default:
+		return ErrorClassOther
+	}
+}
+
+// resultsLen returns the number of documents decoded into results, a pointer to a slice, or 0 if results is not one
+func resultsLen(results interface{}) int {
+	rv := reflect.ValueOf(results)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return 0
+	}
+
+	return rv.Len()
+}
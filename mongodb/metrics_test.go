@@ -0,0 +1,125 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOperationMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+	c := NewCollection(client.Database("test-db").Collection("test-collection"))
+
+	var noErr error
+	_, find := c.startOperation(ctx, "Find")
+	find.returned = 3
+	find.end(&noErr)
+
+	_, update := c.startOperation(ctx, "UpdateMany")
+	update.modified = 2
+	update.end(&noErr)
+
+	notFound := ErrNoDocumentFound
+	_, findOne := c.startOperation(ctx, "FindOne")
+	findOne.end(&notFound)
+
+	_, count := c.startOperation(ctx, "Count")
+	count.retried(1, time.Millisecond, mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"})
+
+	Convey("Given Collection operations that succeeded and failed", t, func() {
+		Convey("Then the duration of every operation is recorded", func() {
+			rm := collect(ctx, reader)
+			duration := findMetric(rm, OperationDurationMetric).Data.(metricdata.Histogram[float64])
+			So(duration.DataPoints, ShouldHaveLength, 3)
+			for _, dp := range duration.DataPoints {
+				So(dp.Count, ShouldEqual, 1)
+				So(attributeValue(dp.Attributes, dbNamespaceKey), ShouldEqual, "test-db")
+				So(attributeValue(dp.Attributes, dbCollectionKey), ShouldEqual, "test-collection")
+			}
+		})
+
+		Convey("Then the documents returned and modified are recorded by operation", func() {
+			rm := collect(ctx, reader)
+			returned := findMetric(rm, DocumentsReturnedMetric).Data.(metricdata.Sum[int64])
+			So(returned.DataPoints, ShouldHaveLength, 1)
+			So(returned.DataPoints[0].Value, ShouldEqual, 3)
+			So(attributeValue(returned.DataPoints[0].Attributes, dbOperationKey), ShouldEqual, "Find")
+
+			modified := findMetric(rm, DocumentsModifiedMetric).Data.(metricdata.Sum[int64])
+			So(modified.DataPoints, ShouldHaveLength, 1)
+			So(modified.DataPoints[0].Value, ShouldEqual, 2)
+			So(attributeValue(modified.DataPoints[0].Attributes, dbOperationKey), ShouldEqual, "UpdateMany")
+		})
+
+		Convey("Then the failed operation is counted by class of error", func() {
+			rm := collect(ctx, reader)
+			errs := findMetric(rm, OperationErrorsMetric).Data.(metricdata.Sum[int64])
+			So(errs.DataPoints, ShouldHaveLength, 1)
+			So(errs.DataPoints[0].Value, ShouldEqual, 1)
+			So(attributeValue(errs.DataPoints[0].Attributes, dbOperationKey), ShouldEqual, "FindOne")
+			So(attributeValue(errs.DataPoints[0].Attributes, errorTypeKey), ShouldEqual, ErrorClassNotFound)
+		})
+
+		Convey("Then the retried operation is counted by class of error", func() {
+			rm := collect(ctx, reader)
+			retries := findMetric(rm, OperationRetriesMetric).Data.(metricdata.Sum[int64])
+			So(retries.DataPoints, ShouldHaveLength, 1)
+			So(retries.DataPoints[0].Value, ShouldEqual, 1)
+			So(attributeValue(retries.DataPoints[0].Attributes, dbOperationKey), ShouldEqual, "Count")
+			So(attributeValue(retries.DataPoints[0].Attributes, errorTypeKey), ShouldEqual, ErrorClassServer)
+		})
+	})
+}
+
+func TestErrorClass(t *testing.T) {
+	Convey("Errors are classified", t, func() {
+		So(errorClass(ErrNoDocumentFound), ShouldEqual, ErrorClassNotFound)
+		So(errorClass(Error{inner: context.DeadlineExceeded}), ShouldEqual, ErrorClassTimeout)
+		So(errorClass(Error{inner: mongo.CommandError{Code: 11600, Message: "interrupted"}}), ShouldEqual, ErrorClassServer)
+		So(errorClass(Error{inner: fmt.Errorf("wrapped: %w", mongo.WriteException{})}), ShouldEqual, ErrorClassServer)
+		So(errorClass(errors.New("decode failed")), ShouldEqual, ErrorClassOther)
+	})
+}
+
+func collect(ctx context.Context, reader *sdkmetric.ManualReader) metricdata.ResourceMetrics {
+	var rm metricdata.ResourceMetrics
+	So(reader.Collect(ctx, &rm), ShouldBeNil)
+	return rm
+}
+
+func findMetric(rm metricdata.ResourceMetrics, name string) metricdata.Metrics {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+
+	return metricdata.Metrics{}
+}
+
+func attributeValue(set attribute.Set, key attribute.Key) string {
+	v, _ := set.Value(key)
+	return v.AsString()
+}
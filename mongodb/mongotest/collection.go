@@ -0,0 +1,655 @@
+package mongotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const duplicateKeyCode = 11000
+
+// Collection is an in-memory implementation of mongodb.CollectionAPI, for unit tests of code using a collection that
+// do not need a MongoDB server. Documents are stored in insertion order, which is also the order in which an operation
+// on a single document chooses between several matching documents
+// Queries, updates, projections and pipelines using features that are not supported return an error wrapping
+// ErrUnsupported, rather than giving results that differ from those of MongoDB
+type Collection struct {
+	mu   sync.Mutex
+	docs []bson.Raw
+	now  func() time.Time
+}
+
+var _ mongoDriver.CollectionAPI = &Collection{}
+
+// NewCollection creates a new, empty, in-memory collection
+func NewCollection() *Collection {
+	return &Collection{now: time.Now}
+}
+
+// Documents returns a copy of the documents in the collection, in insertion order
+func (c *Collection) Documents() []bson.D {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, _ := c.load()
+
+	return docs
+}
+
+// load returns copies of the stored documents, which can be modified without affecting the collection
+func (c *Collection) load() ([]bson.D, error) {
+	docs := make([]bson.D, len(c.docs))
+	for i, raw := range c.docs {
+		if err := bson.Unmarshal(raw, &docs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// query returns copies of the stored documents that satisfy the filter, and their positions in the collection
+func (c *Collection) query(filter interface{}) ([]bson.D, []int, error) {
+	f, err := toDocument(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docs, err := c.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		matched   []bson.D
+		positions []int
+	)
+	for i, doc := range docs {
+		ok, err := matches(doc, f)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			matched = append(matched, doc)
+			positions = append(positions, i)
+		}
+	}
+
+	return matched, positions, nil
+}
+
+// Distinct returns the list of distinct values for the given field name in the collection
+func (c *Collection) Distinct(ctx context.Context, fieldName string, filter interface{}) (_ []interface{}, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, _, err := c.query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []interface{}{}
+	for _, doc := range docs {
+		for _, v := range lookup(doc, splitPath(fieldName)) {
+			values := []interface{}{v}
+			if a, ok := v.(bson.A); ok {
+				values = a
+			}
+			for _, value := range values {
+				if !contains(results, value) {
+					results = append(results, value)
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if compare(v, value) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of documents in the collection that satisfy the given filter
+// Sort and Projection options are ignored. A Limit option <=0 is ignored, and a count of all documents is returned
+func (c *Collection) Count(ctx context.Context, filter interface{}, opts ...mongoDriver.FindOption) (_ int, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, _, err := c.query(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	fo := mongoDriver.ResolveFindOptions(opts...)
+
+	return len(limit(skip(docs, fo.Skip), fo.Limit)), nil
+}
+
+// Find returns the total number of documents in the collection that satisfy the given filter (restricted by the
+// given options), with the actual documents provided in the results parameter (which must be a non nil pointer
+// to a slice of the expected document type)
+// If the WithoutTotalCount option is given, -1 is returned in place of the total count
+// If no sort order option is provided a default sort order of 'ascending _id' is used (bson.M{"_id": 1})
+func (c *Collection) Find(ctx context.Context, filter, results interface{}, opts ...mongoDriver.FindOption) (_ int, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, _, err := c.query(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	fo := mongoDriver.ResolveFindOptions(opts...)
+	totalCount := len(docs)
+	if fo.WithoutTotalCount {
+		totalCount = -1
+	}
+	if fo.Limit < 0 || (fo.Limit == 0 && fo.ObeyZeroLimit) {
+		return totalCount, nil
+	}
+
+	if fo.Sort == nil {
+		fo.Sort = bson.M{"_id": 1}
+	}
+	if err = sortDocuments(docs, fo.Sort); err != nil {
+		return 0, err
+	}
+
+	docs = limit(skip(docs, fo.Skip), fo.Limit)
+	for i := range docs {
+		if docs[i], err = project(docs[i], fo.Projection); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = decodeAll(docs, results); err != nil {
+		return 0, err
+	}
+
+	return totalCount, nil
+}
+
+// FindOne returns a single document in the collection that satisfies the given filter (restricted by the
+// given options), with the actual document provided in the result parameter (which must be a non nil pointer
+// to a document of the expected type)
+// If no document could be found, an ErrNoDocumentFound error is returned
+func (c *Collection) FindOne(ctx context.Context, filter interface{}, result interface{}, opts ...mongoDriver.FindOption) (err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fo := mongoDriver.ResolveFindOptions(opts...)
+	doc, _, err := c.findOne(filter, fo)
+	if err != nil {
+		return err
+	}
+
+	if doc, err = project(doc, fo.Projection); err != nil {
+		return err
+	}
+
+	return decode(doc, result)
+}
+
+// findOne returns the first document that satisfies the filter, in the order given by the options, and its position
+// in the collection
+func (c *Collection) findOne(filter interface{}, fo mongoDriver.FindOptions) (bson.D, int, error) {
+	docs, positions, err := c.query(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indexes, err := sortedIndexes(docs, fo.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if fo.Skip < 0 || fo.Skip >= int64(len(indexes)) {
+		return nil, 0, mongoDriver.ErrNoDocumentFound
+	}
+	first := indexes[fo.Skip]
+
+	return docs[first], positions[first], nil
+}
+
+// FindOneAndUpdate returns a single document in the collection that satisfies the given filter (restricted by the
+// given options), with the actual document provided in the result parameter (which must be a non nil pointer
+// to a document of the expected type), and applies the update to it
+// The document is returned as it was before the update, unless the ReturnDocument(options.After) option is given
+// If no document could be found, an ErrNoDocumentFound error is returned
+func (c *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, result interface{}, opts ...mongoDriver.FindOption) (err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	u, err := toUpdate(update)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fo := mongoDriver.ResolveFindOptions(opts...)
+	doc, position, err := c.findOne(filter, fo)
+	if err != nil {
+		return err
+	}
+
+	before, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if doc, _, err = c.update(position, u); err != nil {
+		return err
+	}
+
+	if fo.ReturnDocument != options.After {
+		if err = bson.Unmarshal(before, &doc); err != nil {
+			return err
+		}
+	}
+	if doc, err = project(doc, fo.Projection); err != nil {
+		return err
+	}
+
+	return decode(doc, result)
+}
+
+// update applies the update to the stored document at the given position, returning the updated document and
+// whether it was modified
+func (c *Collection) update(position int, update bson.D) (bson.D, bool, error) {
+	var doc bson.D
+	if err := bson.Unmarshal(c.docs[position], &doc); err != nil {
+		return nil, false, err
+	}
+
+	id, _ := get(doc, "_id")
+	doc, err := applyUpdate(doc, update, c.now(), false)
+	if err != nil {
+		return nil, false, err
+	}
+	if newID, _ := get(doc, "_id"); compare(id, newID) != 0 {
+		return nil, false, fmt.Errorf("performing an update on the path '_id' would modify the immutable field '_id'")
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	if bytes.Equal(raw, c.docs[position]) {
+		return doc, false, nil
+	}
+	c.docs[position] = raw
+
+	return doc, true, nil
+}
+
+// toUpdate returns the update document as a bson.D. Update pipelines are not supported
+func toUpdate(update interface{}) (bson.D, error) {
+	if v := reflect.ValueOf(update); v.Kind() == reflect.Slice && v.Type() != reflect.TypeOf(bson.D{}) {
+		return nil, unsupported("update pipelines")
+	}
+
+	u, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+	if len(u) == 0 {
+		return nil, errNoUpdateOperators
+	}
+
+	return u, nil
+}
+
+// Insert creates a single document in the collection
+// Deprecated: Use InsertOne
+func (c *Collection) Insert(ctx context.Context, document interface{}) (*mongoDriver.CollectionInsertResult, error) {
+	return c.InsertOne(ctx, document)
+}
+
+// InsertOne creates a single document in the collection, adding an ObjectID _id if the document has no _id
+// If a document with the same _id exists, a duplicate key mongo.WriteException is returned
+func (c *Collection) InsertOne(ctx context.Context, document interface{}) (_ *mongoDriver.CollectionInsertResult, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, err := c.insert(doc)
+	if err != nil {
+		return nil, writeException(err)
+	}
+
+	return &mongoDriver.CollectionInsertResult{InsertedId: id}, nil
+}
+
+// InsertMany creates multiple documents in the collection, in order, adding an ObjectID _id to any document that has
+// no _id
+// If a document with the same _id as one of the documents exists, the documents before it are inserted and a
+// duplicate key mongo.BulkWriteException is returned
+func (c *Collection) InsertMany(ctx context.Context, documents []interface{}) (_ *mongoDriver.CollectionInsertManyResult, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 {
+		return nil, mongo.ErrEmptySlice
+	}
+
+	docs := make([]bson.D, len(documents))
+	for i, document := range documents {
+		doc, err := toDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &mongoDriver.CollectionInsertManyResult{}
+	for i, doc := range docs {
+		id, err := c.insert(doc)
+		if err != nil {
+			var we mongo.WriteError
+			if !errors.As(err, &we) {
+				return nil, err
+			}
+			we.Index = i
+			return nil, mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: we}}}
+		}
+		result.InsertedIds = append(result.InsertedIds, id)
+	}
+
+	return result, nil
+}
+
+// insert stores the document, adding an _id if it has none, and returns its _id. A duplicate _id is returned as a
+// mongo.WriteError, with the key pattern and value of the violation
+func (c *Collection) insert(doc bson.D) (interface{}, error) {
+	id, ok := get(doc, "_id")
+	if !ok {
+		id = primitive.NewObjectID()
+		doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+	}
+
+	docs, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range docs {
+		if existing, _ := get(d, "_id"); compare(existing, id) == 0 {
+			return nil, duplicateKeyError(id)
+		}
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	c.docs = append(c.docs, raw)
+
+	return id, nil
+}
+
+// duplicateKeyError returns the write error for a document with a duplicate _id
+func duplicateKeyError(id interface{}) error {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "code", Value: duplicateKeyCode},
+		{Key: "keyPattern", Value: bson.D{{Key: "_id", Value: 1}}},
+		{Key: "keyValue", Value: bson.D{{Key: "_id", Value: id}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return mongo.WriteError{
+		Code:    duplicateKeyCode,
+		Message: fmt.Sprintf("E11000 duplicate key error collection: index: _id_ dup key: { _id: %v }", id),
+		Raw:     raw,
+	}
+}
+
+// wrapError wraps the error returned by an operation, as a mongodb.Collection does
+func wrapError(err *error) {
+	*err = mongoDriver.WrapError(*err)
+}
+
+// writeException returns a write error as a mongo.WriteException, as returned by single document writes
+func writeException(err error) error {
+	var we mongo.WriteError
+	if !errors.As(err, &we) {
+		return err
+	}
+
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{we}}
+}
+
+// Upsert creates or updates a document located by the provided selector
+// Deprecated: Use UpsertOne
+func (c *Collection) Upsert(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.UpsertOne(ctx, selector, update)
+}
+
+// UpsertById creates or updates a document located by the provided id selector
+// Deprecated: Use UpsertOne
+func (c *Collection) UpsertById(ctx context.Context, id interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.UpsertOne(ctx, bson.M{"_id": id}, update)
+}
+
+// UpsertOne creates or updates a document located by the provided selector
+// If the selector does not match any documents, a document made of the equality conditions of the selector, with the
+// update applied, is inserted into the collection
+func (c *Collection) UpsertOne(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.updateRecords(ctx, selector, update, true, false)
+}
+
+// UpdateById modifies a single document located by the provided id selector
+// Deprecated: Use UpdateOne
+func (c *Collection) UpdateById(ctx context.Context, id interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.UpdateOne(ctx, bson.M{"_id": id}, update)
+}
+
+// Update modifies a single document located by the provided selector
+// Deprecated: Use UpdateOne
+func (c *Collection) Update(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.UpdateOne(ctx, selector, update)
+}
+
+// UpdateOne modifies a single document located by the provided selector
+func (c *Collection) UpdateOne(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.updateRecords(ctx, selector, update, false, false)
+}
+
+// UpdateMany modifies multiple documents located by the provided selector
+func (c *Collection) UpdateMany(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+	return c.updateRecords(ctx, selector, update, false, true)
+}
+
+func (c *Collection) updateRecords(ctx context.Context, selector interface{}, update interface{}, upsert, many bool) (_ *mongoDriver.CollectionUpdateResult, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	u, err := toUpdate(update)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, positions, err := c.query(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &mongoDriver.CollectionUpdateResult{}
+	if len(positions) == 0 && upsert {
+		filter, err := toDocument(selector)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := upsertDocument(filter, u, c.now())
+		if err != nil {
+			return nil, err
+		}
+		if result.UpsertedID, err = c.insert(doc); err != nil {
+			return nil, writeException(err)
+		}
+		result.UpsertedCount = 1
+		return result, nil
+	}
+
+	if !many && len(positions) > 1 {
+		positions = positions[:1]
+	}
+	for _, p := range positions {
+		_, modified, err := c.update(p, u)
+		if err != nil {
+			return nil, err
+		}
+		result.MatchedCount++
+		if modified {
+			result.ModifiedCount++
+		}
+	}
+
+	return result, nil
+}
+
+// Delete deletes a single document based on the provided selector
+// Deprecated: Use DeleteOne instead
+func (c *Collection) Delete(ctx context.Context, selector interface{}) (*mongoDriver.CollectionDeleteResult, error) {
+	return c.DeleteOne(ctx, selector)
+}
+
+// DeleteById deletes a document based on the provided id selector
+// Deprecated: Use DeleteOne
+func (c *Collection) DeleteById(ctx context.Context, id interface{}) (*mongoDriver.CollectionDeleteResult, error) {
+	return c.DeleteOne(ctx, bson.M{"_id": id})
+}
+
+// DeleteOne deletes a single document based on the provided selector
+func (c *Collection) DeleteOne(ctx context.Context, selector interface{}) (*mongoDriver.CollectionDeleteResult, error) {
+	return c.deleteRecords(ctx, selector, false)
+}
+
+// DeleteMany deletes multiple documents based on the provided selector
+func (c *Collection) DeleteMany(ctx context.Context, selector interface{}) (*mongoDriver.CollectionDeleteResult, error) {
+	return c.deleteRecords(ctx, selector, true)
+}
+
+func (c *Collection) deleteRecords(ctx context.Context, selector interface{}, many bool) (_ *mongoDriver.CollectionDeleteResult, err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, positions, err := c.query(selector)
+	if err != nil {
+		return nil, err
+	}
+	if !many && len(positions) > 1 {
+		positions = positions[:1]
+	}
+
+	deleted := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		deleted[p] = true
+	}
+	remaining := c.docs[:0:0]
+	for i, raw := range c.docs {
+		if !deleted[i] {
+			remaining = append(remaining, raw)
+		}
+	}
+	c.docs = remaining
+
+	return &mongoDriver.CollectionDeleteResult{DeletedCount: len(positions)}, nil
+}
+
+// Aggregate runs the pipeline on the collection, with the output documents provided in the results parameter (which
+// must be a non nil pointer to a slice of the expected document type)
+// The supported stages are $match, $sort, $skip, $limit, $project and $count
+func (c *Collection) Aggregate(ctx context.Context, pipeline, results interface{}) (err error) {
+	defer wrapError(&err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if docs, err = aggregate(docs, pipeline); err != nil {
+		return err
+	}
+
+	return decodeAll(docs, results)
+}
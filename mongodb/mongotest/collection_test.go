@@ -0,0 +1,501 @@
+package mongotest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/dp-mongodb/v3/mongodb/mongotest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testDocument struct {
+	ID      int       `bson:"_id"`
+	Name    string    `bson:"name,omitempty"`
+	State   string    `bson:"state,omitempty"`
+	Count   int       `bson:"count,omitempty"`
+	Tags    []string  `bson:"tags,omitempty"`
+	Updated time.Time `bson:"updated,omitempty"`
+}
+
+func setUpCollection(ctx context.Context) *mongotest.Collection {
+	c := mongotest.NewCollection()
+	_, err := c.InsertMany(ctx, []interface{}{
+		testDocument{ID: 3, Name: "charlie", State: "published", Count: 30, Tags: []string{"b", "c"}},
+		testDocument{ID: 1, Name: "alpha", State: "created", Count: 10, Tags: []string{"a"}},
+		testDocument{ID: 2, Name: "bravo", State: "published", Count: 20},
+		testDocument{ID: 4, Name: "delta", Count: 40, Tags: []string{"c"}},
+	})
+	So(err, ShouldBeNil)
+
+	return c
+}
+
+func ids(docs []testDocument) []int {
+	var ids []int
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+
+	return ids
+}
+
+func TestCollection_Find(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("When Find is called without a filter", func() {
+			var results []testDocument
+			count, err := c.Find(ctx, bson.D{}, &results)
+
+			Convey("Then all documents are returned in ascending _id order", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 4)
+				So(ids(results), ShouldResemble, []int{1, 2, 3, 4})
+			})
+		})
+
+		Convey("When Find is called with query operators", func() {
+			filters := []struct {
+				name   string
+				filter interface{}
+				ids    []int
+			}{
+				{"equality", bson.M{"state": "published"}, []int{2, 3}},
+				{"$eq", bson.M{"name": bson.M{"$eq": "alpha"}}, []int{1}},
+				{"$ne", bson.M{"state": bson.M{"$ne": "published"}}, []int{1, 4}},
+				{"$in", bson.M{"_id": bson.M{"$in": bson.A{1, 4, 5}}}, []int{1, 4}},
+				{"$nin", bson.M{"_id": bson.M{"$nin": bson.A{1, 4}}}, []int{2, 3}},
+				{"$gt and $lte", bson.M{"count": bson.M{"$gt": 10, "$lte": 30}}, []int{2, 3}},
+				{"$exists", bson.M{"state": bson.M{"$exists": false}}, []int{4}},
+				{"null", bson.M{"state": nil}, []int{4}},
+				{"array element", bson.M{"tags": "c"}, []int{3, 4}},
+				{"$and", bson.M{"$and": bson.A{bson.M{"state": "published"}, bson.M{"count": bson.M{"$gte": 30}}}}, []int{3}},
+				{"$or", bson.M{"$or": bson.A{bson.M{"name": "alpha"}, bson.M{"name": "delta"}}}, []int{1, 4}},
+				{"$nor", bson.M{"$nor": bson.A{bson.M{"name": "alpha"}, bson.M{"name": "delta"}}}, []int{2, 3}},
+				{"$not", bson.M{"count": bson.M{"$not": bson.M{"$gt": 20}}}, []int{1, 2}},
+				{"type mismatch", bson.M{"name": bson.M{"$gt": 1}}, nil},
+				{"numeric widths", bson.M{"count": int64(20)}, []int{2}},
+			}
+
+			for _, f := range filters {
+				Convey("Then the documents matching the "+f.name+" filter are returned", func() {
+					var results []testDocument
+					_, err := c.Find(ctx, f.filter, &results)
+
+					So(err, ShouldBeNil)
+					So(ids(results), ShouldResemble, f.ids)
+				})
+			}
+		})
+
+		Convey("When Find is called with sort, offset and limit options", func() {
+			var results []testDocument
+			count, err := c.Find(ctx, bson.M{"count": bson.M{"$gt": 10}}, &results,
+				mongoDriver.Sort(bson.D{{Key: "count", Value: -1}}), mongoDriver.Offset(1), mongoDriver.Limit(2))
+
+			Convey("Then the page of documents is returned with the total count", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 3)
+				So(ids(results), ShouldResemble, []int{3, 2})
+			})
+		})
+
+		Convey("When Find is called with a sort on an array field", func() {
+			var ascending, descending []testDocument
+			_, err := c.Find(ctx, bson.M{"tags": bson.M{"$exists": true}}, &ascending, mongoDriver.Sort(bson.M{"tags": 1}))
+			So(err, ShouldBeNil)
+			_, err = c.Find(ctx, bson.M{"tags": bson.M{"$exists": true}}, &descending, mongoDriver.Sort(bson.M{"tags": -1}))
+			So(err, ShouldBeNil)
+
+			Convey("Then documents are ordered by their smallest element ascending, and largest descending", func() {
+				So(ids(ascending), ShouldResemble, []int{1, 3, 4})
+				So(ids(descending), ShouldResemble, []int{3, 4, 1})
+			})
+		})
+
+		Convey("When Find is called with a limit of 0", func() {
+			var results []testDocument
+			count, err := c.Find(ctx, bson.D{}, &results, mongoDriver.Limit(0))
+
+			Convey("Then only the total count is returned", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 4)
+				So(results, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When Find is called with the WithoutTotalCount option", func() {
+			var results []testDocument
+			count, err := c.Find(ctx, bson.D{}, &results, mongoDriver.WithoutTotalCount())
+
+			Convey("Then -1 is returned in place of the total count", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, -1)
+				So(results, ShouldHaveLength, 4)
+			})
+		})
+
+		Convey("When Find is called with an inclusion projection", func() {
+			var results []bson.M
+			_, err := c.Find(ctx, bson.M{"_id": 1}, &results, mongoDriver.Projection(bson.M{"name": 1}))
+
+			Convey("Then only the projected fields and _id are returned", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldResemble, []bson.M{{"_id": int32(1), "name": "alpha"}})
+			})
+		})
+
+		Convey("When Find is called with an exclusion projection", func() {
+			var results []bson.M
+			_, err := c.Find(ctx, bson.M{"_id": 2}, &results, mongoDriver.Projection(bson.M{"_id": 0, "count": 0}))
+
+			Convey("Then the excluded fields are not returned", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldResemble, []bson.M{{"name": "bravo", "state": "published"}})
+			})
+		})
+
+		Convey("When Find is called with an unsupported operator", func() {
+			var results []testDocument
+			_, err := c.Find(ctx, bson.M{"name": bson.M{"$regex": "^a"}}, &results)
+
+			Convey("Then an ErrUnsupported error is returned", func() {
+				So(errors.Is(err, mongotest.ErrUnsupported), ShouldBeTrue)
+			})
+		})
+
+		Convey("When Find is called with a cancelled context", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			var results []testDocument
+			_, err := c.Find(cancelled, bson.D{}, &results)
+
+			Convey("Then the context error is returned, wrapped as by a mongodb.Collection", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(mongoDriver.IsServerErr(err), ShouldBeTrue)
+				So(mongoDriver.IsTimeout(err), ShouldBeFalse)
+			})
+		})
+
+		Convey("When Find is called with a context whose deadline has passed", func() {
+			expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+			defer cancel()
+			var results []testDocument
+			_, err := c.Find(expired, bson.D{}, &results)
+
+			Convey("Then IsTimeout reports the error as a timeout, as for a mongodb.Collection", func() {
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+				So(mongoDriver.IsTimeout(err), ShouldBeTrue)
+				So(mongoDriver.IsServerErr(err), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestCollection_FindOne(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("When FindOne is called with a sort option", func() {
+			var result testDocument
+			err := c.FindOne(ctx, bson.M{"state": "published"}, &result, mongoDriver.Sort(bson.M{"count": -1}))
+
+			Convey("Then the first document in the sort order is returned", func() {
+				So(err, ShouldBeNil)
+				So(result.ID, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When FindOne is called with a filter that matches no documents", func() {
+			var result testDocument
+			err := c.FindOne(ctx, bson.M{"state": "unknown"}, &result)
+
+			Convey("Then ErrNoDocumentFound is returned", func() {
+				So(err, ShouldEqual, mongoDriver.ErrNoDocumentFound)
+			})
+		})
+
+		Convey("When FindOneAndUpdate is called", func() {
+			var before, after testDocument
+			err := c.FindOneAndUpdate(ctx, bson.M{"_id": 1}, bson.M{"$inc": bson.M{"count": 1}}, &before)
+			So(err, ShouldBeNil)
+			err = c.FindOneAndUpdate(ctx, bson.M{"_id": 1}, bson.M{"$inc": bson.M{"count": 1}}, &after, mongoDriver.ReturnDocument(options.After))
+			So(err, ShouldBeNil)
+
+			Convey("Then the document is returned as it was before the update, unless the ReturnDocument option is given", func() {
+				So(before.Count, ShouldEqual, 10)
+				So(after.Count, ShouldEqual, 12)
+			})
+		})
+	})
+}
+
+func TestCollection_CountAndDistinct(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("Then Count returns the number of matching documents, restricted by the offset and limit", func() {
+			count, err := c.Count(ctx, bson.M{"count": bson.M{"$gte": 20}})
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+
+			count, err = c.Count(ctx, bson.M{"count": bson.M{"$gte": 20}}, mongoDriver.Offset(1), mongoDriver.Limit(1))
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Then Distinct returns the distinct values, including array elements", func() {
+			values, err := c.Distinct(ctx, "tags", bson.D{})
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []interface{}{"b", "c", "a"})
+		})
+	})
+}
+
+func TestCollection_Insert(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an empty in-memory collection", t, func() {
+		c := mongotest.NewCollection()
+
+		Convey("When a document without an _id is inserted", func() {
+			result, err := c.InsertOne(ctx, bson.M{"name": "alpha"})
+
+			Convey("Then an ObjectID _id is generated", func() {
+				So(err, ShouldBeNil)
+				So(result.InsertedId, ShouldHaveSameTypeAs, primitive.ObjectID{})
+				So(c.Documents(), ShouldResemble, []bson.D{{{Key: "_id", Value: result.InsertedId}, {Key: "name", Value: "alpha"}}})
+			})
+		})
+
+		Convey("When a document with an existing _id is inserted", func() {
+			_, err := c.InsertOne(ctx, bson.M{"_id": 1})
+			So(err, ShouldBeNil)
+			_, err = c.InsertOne(ctx, bson.M{"_id": 1})
+
+			Convey("Then a duplicate key error is returned", func() {
+				So(mongo.IsDuplicateKeyError(err), ShouldBeTrue)
+				So(c.Documents(), ShouldHaveLength, 1)
+			})
+
+			Convey("Then the error is a server error, wrapped as by a mongodb.Collection", func() {
+				So(mongoDriver.IsServerErr(err), ShouldBeTrue)
+				So(mongoDriver.IsTimeout(err), ShouldBeFalse)
+				So(mongoDriver.IsDuplicateKey(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When documents including a duplicate _id are inserted with InsertMany", func() {
+			_, err := c.InsertMany(ctx, []interface{}{bson.M{"_id": 1}, bson.M{"_id": 2}, bson.M{"_id": 1}, bson.M{"_id": 3}})
+
+			Convey("Then the documents before the duplicate are inserted, and a duplicate key error is returned", func() {
+				So(mongoDriver.IsDuplicateKey(err), ShouldBeTrue)
+				So(c.Documents(), ShouldHaveLength, 2)
+
+				writeErrors := mongoDriver.WriteErrors(err)
+				So(writeErrors, ShouldHaveLength, 1)
+				So(writeErrors[0].Index, ShouldEqual, 2)
+				So(writeErrors[0].KeyValue.Lookup("_id").Int32(), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestCollection_Update(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("When UpdateOne is called with update operators", func() {
+			start := time.Now().Add(-time.Second)
+			result, err := c.UpdateOne(ctx, bson.M{"_id": 3}, bson.M{
+				"$set":         bson.M{"state": "archived"},
+				"$unset":       bson.M{"name": ""},
+				"$inc":         bson.M{"count": 5},
+				"$push":        bson.M{"tags": bson.M{"$each": bson.A{"d", "e"}}},
+				"$currentDate": bson.M{"updated": true},
+			})
+
+			Convey("Then the document is updated", func() {
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &mongoDriver.CollectionUpdateResult{MatchedCount: 1, ModifiedCount: 1})
+
+				var doc testDocument
+				So(c.FindOne(ctx, bson.M{"_id": 3}, &doc), ShouldBeNil)
+				So(doc.Name, ShouldBeEmpty)
+				So(doc.State, ShouldEqual, "archived")
+				So(doc.Count, ShouldEqual, 35)
+				So(doc.Tags, ShouldResemble, []string{"b", "c", "d", "e"})
+				So(doc.Updated, ShouldHappenAfter, start)
+			})
+		})
+
+		Convey("When UpdateOne is called with an update that does not change the document", func() {
+			result, err := c.UpdateOne(ctx, bson.M{"_id": 1}, bson.M{"$set": bson.M{"state": "created"}})
+
+			Convey("Then the document is matched but not modified", func() {
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &mongoDriver.CollectionUpdateResult{MatchedCount: 1})
+			})
+		})
+
+		Convey("When UpdateMany is called", func() {
+			result, err := c.UpdateMany(ctx, bson.M{"state": "published"}, bson.M{"$set": bson.M{"state": "archived"}})
+
+			Convey("Then all matching documents are updated", func() {
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &mongoDriver.CollectionUpdateResult{MatchedCount: 2, ModifiedCount: 2})
+
+				count, err := c.Count(ctx, bson.M{"state": "archived"})
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When UpdateOne is called with a document without update operators", func() {
+			_, err := c.UpdateOne(ctx, bson.M{"_id": 1}, bson.M{"state": "archived"})
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When UpsertOne is called with a selector that matches no documents", func() {
+			result, err := c.UpsertOne(ctx, bson.M{"name": "echo", "count": bson.M{"$gt": 1}}, bson.M{
+				"$set":         bson.M{"state": "created"},
+				"$setOnInsert": bson.M{"count": 50},
+			})
+
+			Convey("Then a document is inserted from the selector's equality conditions and the update", func() {
+				So(err, ShouldBeNil)
+				So(result.UpsertedCount, ShouldEqual, 1)
+				So(result.UpsertedID, ShouldHaveSameTypeAs, primitive.ObjectID{})
+
+				var doc bson.M
+				So(c.FindOne(ctx, bson.M{"_id": result.UpsertedID}, &doc, mongoDriver.Projection(bson.M{"_id": 0})), ShouldBeNil)
+				So(doc, ShouldResemble, bson.M{"name": "echo", "state": "created", "count": int32(50)})
+			})
+		})
+
+		Convey("When UpsertOne is called with a selector that matches a document", func() {
+			result, err := c.UpsertOne(ctx, bson.M{"_id": 2}, bson.M{"$setOnInsert": bson.M{"count": 50}})
+
+			Convey("Then $setOnInsert is not applied", func() {
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, &mongoDriver.CollectionUpdateResult{MatchedCount: 1})
+			})
+		})
+
+		Convey("When UpsertOne is called with a selector that matches no documents, but has the _id of a document", func() {
+			_, err := c.UpsertOne(ctx, bson.M{"_id": 2, "state": "archived"}, bson.M{"$set": bson.M{"count": 50}})
+
+			Convey("Then a duplicate key error is returned, with the key pattern and value of the _id", func() {
+				So(mongo.IsDuplicateKeyError(err), ShouldBeTrue)
+				writeErrors := mongoDriver.WriteErrors(err)
+				So(writeErrors, ShouldHaveLength, 1)
+				So(writeErrors[0].KeyPattern.Lookup("_id").Int32(), ShouldEqual, 1)
+				So(writeErrors[0].KeyValue.Lookup("_id").Int32(), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When UpsertOne is called with an update that cannot be applied to the document to insert", func() {
+			_, err := c.UpsertOne(ctx, bson.M{"name": "echo"}, bson.M{"$inc": bson.M{"name": 1}})
+
+			Convey("Then the error is returned unchanged, rather than as a duplicate key error", func() {
+				So(err, ShouldNotBeNil)
+				So(mongo.IsDuplicateKeyError(err), ShouldBeFalse)
+				So(mongoDriver.WriteErrors(err), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestCollection_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("When DeleteOne is called", func() {
+			result, err := c.DeleteOne(ctx, bson.M{"state": "published"})
+
+			Convey("Then the first matching document is deleted", func() {
+				So(err, ShouldBeNil)
+				So(result.DeletedCount, ShouldEqual, 1)
+
+				var remaining []testDocument
+				_, err = c.Find(ctx, bson.D{}, &remaining)
+				So(err, ShouldBeNil)
+				So(ids(remaining), ShouldResemble, []int{1, 2, 4})
+			})
+		})
+
+		Convey("When DeleteMany is called", func() {
+			result, err := c.DeleteMany(ctx, bson.M{"count": bson.M{"$gte": 20}})
+
+			Convey("Then all matching documents are deleted", func() {
+				So(err, ShouldBeNil)
+				So(result.DeletedCount, ShouldEqual, 3)
+				So(c.Documents(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestCollection_Aggregate(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory collection with documents", t, func() {
+		c := setUpCollection(ctx)
+
+		Convey("When Aggregate is called with a supported pipeline", func() {
+			var results []bson.M
+			err := c.Aggregate(ctx, mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"count": bson.M{"$gte": 20}}}},
+				{{Key: "$sort", Value: bson.M{"count": -1}}},
+				{{Key: "$skip", Value: 1}},
+				{{Key: "$limit", Value: 1}},
+				{{Key: "$project", Value: bson.M{"name": 1, "_id": 0}}},
+			}, &results)
+
+			Convey("Then the output documents are returned", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldResemble, []bson.M{{"name": "charlie"}})
+			})
+		})
+
+		Convey("When Aggregate is called with a $count stage", func() {
+			var results []bson.M
+			err := c.Aggregate(ctx, bson.A{bson.M{"$match": bson.M{"state": "published"}}, bson.M{"$count": "n"}}, &results)
+
+			Convey("Then the count is returned", func() {
+				So(err, ShouldBeNil)
+				So(results, ShouldResemble, []bson.M{{"n": int32(2)}})
+			})
+		})
+
+		Convey("When Aggregate is called with an unsupported stage", func() {
+			var results []bson.M
+			err := c.Aggregate(ctx, bson.A{bson.M{"$group": bson.M{"_id": "$state"}}}, &results)
+
+			Convey("Then an ErrUnsupported error is returned", func() {
+				So(errors.Is(err, mongotest.ErrUnsupported), ShouldBeTrue)
+			})
+		})
+	})
+}
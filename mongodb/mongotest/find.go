@@ -0,0 +1,261 @@
+package mongotest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// sortDocuments sorts the documents, in place, by the sort order (e.g. bson.D{{Key: "state", Value: 1}})
+func sortDocuments(docs []bson.D, order interface{}) error {
+	indexes, err := sortedIndexes(docs, order)
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]bson.D, len(docs))
+	for i, n := range indexes {
+		sorted[i] = docs[n]
+	}
+	copy(docs, sorted)
+
+	return nil
+}
+
+// sortedIndexes returns the indexes of the documents in the sort order. A missing field sorts as null, and an array
+// field by its smallest element, or its largest for a descending sort
+func sortedIndexes(docs []bson.D, order interface{}) ([]int, error) {
+	indexes := make([]int, len(docs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	if order == nil {
+		return indexes, nil
+	}
+
+	keys, err := toDocument(order)
+	if err != nil {
+		return nil, err
+	}
+
+	directions := make([]int, len(keys))
+	for i, k := range keys {
+		switch d := toFloat(k.Value); d {
+		case 1, -1:
+			directions[i] = int(d)
+		default:
+			return nil, unsupported(fmt.Sprintf("sort order %v for %s", k.Value, k.Key))
+		}
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		for n, k := range keys {
+			a := sortValue(docs[indexes[i]], k.Key, directions[n])
+			b := sortValue(docs[indexes[j]], k.Key, directions[n])
+			if c := compare(a, b); c != 0 {
+				return c*directions[n] < 0
+			}
+		}
+		return false
+	})
+
+	return indexes, nil
+}
+
+func sortValue(doc bson.D, field string, direction int) interface{} {
+	values := candidates(lookup(doc, splitPath(field)))
+	if len(values) == 0 {
+		return nil
+	}
+
+	v := values[0]
+	for _, c := range values[1:] {
+		if _, isArray := c.(bson.A); isArray {
+			continue
+		}
+		if _, isArray := v.(bson.A); isArray || compare(c, v)*direction < 0 {
+			v = c
+		}
+	}
+
+	return v
+}
+
+// project returns the document with the projection (e.g. bson.M{"state": 1} or bson.M{"links": 0}) applied. The _id
+// field is included unless excluded explicitly
+func project(doc bson.D, projection interface{}) (bson.D, error) {
+	if projection == nil {
+		return doc, nil
+	}
+
+	spec, err := toDocument(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	inclusion, includeID := false, true
+	for _, f := range spec {
+		if strings.HasPrefix(f.Key, "$") || typeOrder(f.Value) > typeOrder(false) {
+			return nil, unsupported("projection of " + f.Key)
+		}
+		if f.Key == "_id" {
+			includeID = isTruthy(f.Value)
+			continue
+		}
+		inclusion = isTruthy(f.Value)
+	}
+
+	if !inclusion {
+		for _, f := range spec {
+			if !isTruthy(f.Value) {
+				doc = unset(doc, splitPath(f.Key))
+			}
+		}
+		return doc, nil
+	}
+
+	projected := bson.D{}
+	if id, ok := get(doc, "_id"); ok && includeID {
+		projected = append(projected, bson.E{Key: "_id", Value: id})
+	}
+	for _, f := range spec {
+		if f.Key == "_id" || !isTruthy(f.Value) {
+			continue
+		}
+		values := lookup(doc, splitPath(f.Key))
+		if len(values) == 0 {
+			continue
+		}
+		if projected, err = set(projected, splitPath(f.Key), values[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return projected, nil
+}
+
+// aggregate returns the output of the pipeline for the documents
+// The supported stages are $match, $sort, $skip, $limit, $project and $count
+func aggregate(docs []bson.D, pipeline interface{}) ([]bson.D, error) {
+	stages, err := toArray(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range stages {
+		stage, ok := s.(bson.D)
+		if !ok || len(stage) != 1 {
+			return nil, fmt.Errorf("a pipeline stage specification must be a document with a single field")
+		}
+
+		name, arg := stage[0].Key, stage[0].Value
+		switch name {
+		case "$match":
+			filter, ok := arg.(bson.D)
+			if !ok {
+				return nil, fmt.Errorf("the $match filter must be a document")
+			}
+			if docs, err = filterDocuments(docs, filter); err != nil {
+				return nil, err
+			}
+		case "$sort":
+			if err = sortDocuments(docs, arg); err != nil {
+				return nil, err
+			}
+		case "$skip":
+			docs = skip(docs, int64(toFloat(arg)))
+		case "$limit":
+			docs = limit(docs, int64(toFloat(arg)))
+		case "$project":
+			for i := range docs {
+				if docs[i], err = project(docs[i], arg); err != nil {
+					return nil, err
+				}
+			}
+		case "$count":
+			field, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("the count field must be a non-empty string")
+			}
+			if len(docs) == 0 {
+				return nil, nil
+			}
+			docs = []bson.D{{{Key: field, Value: int32(len(docs))}}}
+		default:
+			return nil, unsupported("pipeline stage " + name)
+		}
+	}
+
+	return docs, nil
+}
+
+func filterDocuments(docs []bson.D, filter bson.D) ([]bson.D, error) {
+	var matched []bson.D
+	for _, doc := range docs {
+		ok, err := matches(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, doc)
+		}
+	}
+
+	return matched, nil
+}
+
+func skip(docs []bson.D, n int64) []bson.D {
+	if n >= int64(len(docs)) {
+		return nil
+	}
+	if n > 0 {
+		return docs[n:]
+	}
+
+	return docs
+}
+
+func limit(docs []bson.D, n int64) []bson.D {
+	if n > 0 && n < int64(len(docs)) {
+		return docs[:n]
+	}
+
+	return docs
+}
+
+// decodeAll decodes the documents into results, which must be a pointer to a slice
+func decodeAll(docs []bson.D, results interface{}) error {
+	rv := reflect.ValueOf(results)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results argument must be a pointer to a slice, but was a %s", rv.Kind())
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, len(docs))
+	for _, doc := range docs {
+		b, err := bson.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err = bson.Unmarshal(b, elem.Interface()); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem.Elem())
+	}
+	rv.Elem().Set(slice)
+
+	return nil
+}
+
+// decode decodes the document into result, which must be a pointer
+func decode(doc bson.D, result interface{}) error {
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(b, result)
+}
@@ -0,0 +1,54 @@
+// Package mongotest provides helpers for tests of code using the mongodb package, including an in-memory
+// implementation of mongodb.CollectionAPI
+package mongotest
+
+import (
+	"strings"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+)
+
+// TestingT is the subset of testing.TB used by the helpers, so that they can be used with any testing framework
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertNoCollectionScan fails the test if the query plan scans the whole collection, rather than using an index,
+// returning whether the assertion passed, e.g.
+//
+//	plan, err := collection.ExplainFind(ctx, filter, opts...)
+//	mongotest.AssertNoCollectionScan(t, plan)
+func AssertNoCollectionScan(t TestingT, plan *mongoDriver.ExplainPlan) bool {
+	t.Helper()
+
+	if plan == nil {
+		t.Errorf("expected a query plan, but got nil")
+		return false
+	}
+
+	if plan.IsCollectionScan() {
+		t.Errorf("expected the query to use an index, but it performs a %s (plan stages: %s, documents examined: %d, returned: %d)",
+			mongoDriver.StageCollectionScan, strings.Join(plan.Stages, " <- "), plan.DocsExamined, plan.DocsReturned)
+		return false
+	}
+
+	return true
+}
+
+// ShouldNotCollectionScan is a goconvey assertion that the query plan does not scan the whole collection, e.g.
+//
+//	So(plan, mongotest.ShouldNotCollectionScan)
+func ShouldNotCollectionScan(actual interface{}, _ ...interface{}) string {
+	plan, ok := actual.(*mongoDriver.ExplainPlan)
+	if !ok || plan == nil {
+		return "expected a non nil *mongodb.ExplainPlan"
+	}
+
+	if plan.IsCollectionScan() {
+		return "expected the query to use an index, but it performs a " + mongoDriver.StageCollectionScan +
+			" (plan stages: " + strings.Join(plan.Stages, " <- ") + ")"
+	}
+
+	return ""
+}
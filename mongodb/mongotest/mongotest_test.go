@@ -0,0 +1,56 @@
+package mongotest_test
+
+import (
+	"fmt"
+	"testing"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/dp-mongodb/v3/mongodb/mongotest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertNoCollectionScan(t *testing.T) {
+	Convey("Given a query plan using an index", t, func() {
+		plan := &mongoDriver.ExplainPlan{Stages: []string{mongoDriver.StageFetch, mongoDriver.StageIndexScan}, IndexNames: []string{"state_1"}}
+
+		Convey("Then the assertions pass", func() {
+			rt := &recordingT{}
+			So(mongotest.AssertNoCollectionScan(rt, plan), ShouldBeTrue)
+			So(rt.errors, ShouldBeEmpty)
+			So(plan, mongotest.ShouldNotCollectionScan)
+		})
+	})
+
+	Convey("Given a query plan scanning the collection", t, func() {
+		plan := &mongoDriver.ExplainPlan{Stages: []string{mongoDriver.StageSort, mongoDriver.StageCollectionScan}, DocsExamined: 100, DocsReturned: 1}
+
+		Convey("Then the assertions fail, reporting the plan", func() {
+			rt := &recordingT{}
+			So(mongotest.AssertNoCollectionScan(rt, plan), ShouldBeFalse)
+			So(rt.errors, ShouldResemble, []string{
+				"expected the query to use an index, but it performs a COLLSCAN (plan stages: SORT <- COLLSCAN, documents examined: 100, returned: 1)",
+			})
+			So(mongotest.ShouldNotCollectionScan(plan), ShouldEqual, "expected the query to use an index, but it performs a COLLSCAN (plan stages: SORT <- COLLSCAN)")
+		})
+	})
+
+	Convey("Given no query plan", t, func() {
+		Convey("Then the assertions fail", func() {
+			rt := &recordingT{}
+			So(mongotest.AssertNoCollectionScan(rt, nil), ShouldBeFalse)
+			So(rt.errors, ShouldHaveLength, 1)
+			So(mongotest.ShouldNotCollectionScan(nil), ShouldNotBeEmpty)
+		})
+	})
+}
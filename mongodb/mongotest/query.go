@@ -0,0 +1,165 @@
+package mongotest
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// matches returns whether the document satisfies the filter
+// The supported query operators are $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $exists, $not, $and, $or and $nor
+func matches(doc bson.D, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		var (
+			ok  bool
+			err error
+		)
+
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, e.Key, e.Value)
+		default:
+			if strings.HasPrefix(e.Key, "$") {
+				return false, unsupported("query operator " + e.Key)
+			}
+			ok, err = matchField(lookup(doc, splitPath(e.Key)), e.Value)
+		}
+
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchLogical(doc bson.D, operator string, value interface{}) (bool, error) {
+	filters, ok := value.(bson.A)
+	if !ok || len(filters) == 0 {
+		return false, fmt.Errorf("%s must be a nonempty array", operator)
+	}
+
+	for _, f := range filters {
+		filter, ok := f.(bson.D)
+		if !ok {
+			return false, fmt.Errorf("%s elements must be documents", operator)
+		}
+
+		ok, err := matches(doc, filter)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == "$and" && !ok:
+			return false, nil
+		case operator == "$or" && ok:
+			return true, nil
+		case operator == "$nor" && ok:
+			return false, nil
+		}
+	}
+
+	return operator != "$or", nil
+}
+
+// matchField returns whether the values found at a field's path satisfy the condition, which is either a document of
+// query operators or a value the field must equal
+func matchField(values []interface{}, condition interface{}) (bool, error) {
+	if _, ok := condition.(primitive.Regex); ok {
+		return false, unsupported("regular expressions")
+	}
+
+	operators, ok := condition.(bson.D)
+	if !ok || len(operators) == 0 || !strings.HasPrefix(operators[0].Key, "$") {
+		return matchEquals(values, condition), nil
+	}
+
+	for _, op := range operators {
+		ok, err := matchOperator(values, op.Key, op.Value)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchOperator(values []interface{}, operator string, value interface{}) (bool, error) {
+	switch operator {
+	case "$eq":
+		return matchEquals(values, value), nil
+	case "$ne":
+		return !matchEquals(values, value), nil
+	case "$gt":
+		return matchCompare(values, value, func(c int) bool { return c > 0 }), nil
+	case "$gte":
+		return matchCompare(values, value, func(c int) bool { return c >= 0 }), nil
+	case "$lt":
+		return matchCompare(values, value, func(c int) bool { return c < 0 }), nil
+	case "$lte":
+		return matchCompare(values, value, func(c int) bool { return c <= 0 }), nil
+	case "$in", "$nin":
+		list, ok := value.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("%s needs an array", operator)
+		}
+		in := false
+		for _, v := range list {
+			if matchEquals(values, v) {
+				in = true
+				break
+			}
+		}
+		return in == (operator == "$in"), nil
+	case "$exists":
+		return (len(values) > 0) == isTruthy(value), nil
+	case "$not":
+		ok, err := matchField(values, value)
+		return !ok, err
+	default:
+		return false, unsupported("query operator " + operator)
+	}
+}
+
+// candidates returns the values a condition is tested against: the values found at the field's path, and the
+// elements of any of those values that are arrays
+func candidates(values []interface{}) []interface{} {
+	var c []interface{}
+	for _, v := range values {
+		c = append(c, v)
+		if a, ok := v.(bson.A); ok {
+			c = append(c, a...)
+		}
+	}
+
+	return c
+}
+
+// matchEquals returns whether any of the values equals the given value. A null value also matches a missing field
+func matchEquals(values []interface{}, value interface{}) bool {
+	if value == nil && len(values) == 0 {
+		return true
+	}
+
+	for _, v := range candidates(values) {
+		if compare(v, value) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCompare returns whether any of the values of the same type as the given value satisfies the comparison
+func matchCompare(values []interface{}, value interface{}, satisfies func(int) bool) bool {
+	for _, v := range candidates(values) {
+		if typeOrder(v) == typeOrder(value) && satisfies(compare(v, value)) {
+			return true
+		}
+	}
+
+	return false
+}
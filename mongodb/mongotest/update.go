@@ -0,0 +1,170 @@
+package mongotest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNoUpdateOperators = errors.New("update document must contain key beginning with '$'")
+
+// applyUpdate returns the document with the update applied. Fields are only set by $setOnInsert if inserting
+// The supported update operators are $set, $setOnInsert, $unset, $inc, $push (with or without $each) and $currentDate
+func applyUpdate(doc bson.D, update bson.D, now time.Time, inserting bool) (bson.D, error) {
+	if len(update) == 0 {
+		return nil, errNoUpdateOperators
+	}
+
+	var err error
+	for _, op := range update {
+		if !strings.HasPrefix(op.Key, "$") {
+			return nil, errNoUpdateOperators
+		}
+
+		fields, ok := op.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("modifiers for %s must be a document", op.Key)
+		}
+
+		for _, f := range fields {
+			path := splitPath(f.Key)
+			switch op.Key {
+			case "$set":
+				doc, err = set(doc, path, f.Value)
+			case "$setOnInsert":
+				if inserting {
+					doc, err = set(doc, path, f.Value)
+				}
+			case "$unset":
+				doc = unset(doc, path)
+			case "$inc":
+				doc, err = inc(doc, f.Key, f.Value)
+			case "$push":
+				doc, err = push(doc, f.Key, f.Value)
+			case "$currentDate":
+				doc, err = currentDate(doc, f.Key, f.Value, now)
+			default:
+				return nil, unsupported("update operator " + op.Key)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+func inc(doc bson.D, field string, amount interface{}) (bson.D, error) {
+	if typeOrder(amount) != typeOrder(int32(0)) {
+		return nil, fmt.Errorf("cannot increment with non-numeric argument: {%s: %v}", field, amount)
+	}
+
+	values := lookup(doc, splitPath(field))
+	if len(values) == 0 {
+		return set(doc, splitPath(field), amount)
+	}
+	if typeOrder(values[0]) != typeOrder(amount) {
+		return nil, fmt.Errorf("cannot apply $inc to a value of non-numeric type: {%s: %v}", field, values[0])
+	}
+
+	return set(doc, splitPath(field), add(values[0], amount))
+}
+
+// add returns the sum of two numbers, with the type MongoDB gives it
+func add(a, b interface{}) interface{} {
+	switch {
+	case isType[float64](a) || isType[float64](b) || isType[primitive.Decimal128](a) || isType[primitive.Decimal128](b):
+		return toFloat(a) + toFloat(b)
+	case isType[int32](a) && isType[int32](b):
+		sum := int64(a.(int32)) + int64(b.(int32))
+		if sum >= math.MinInt32 && sum <= math.MaxInt32 {
+			return int32(sum)
+		}
+		return sum
+	default:
+		return int64(toFloat(a)) + int64(toFloat(b))
+	}
+}
+
+func isType[T any](v interface{}) bool {
+	_, ok := v.(T)
+	return ok
+}
+
+func push(doc bson.D, field string, value interface{}) (bson.D, error) {
+	items := bson.A{value}
+	if modifiers, ok := value.(bson.D); ok && len(modifiers) > 0 && strings.HasPrefix(modifiers[0].Key, "$") {
+		if len(modifiers) > 1 || modifiers[0].Key != "$each" {
+			return nil, unsupported("$push modifiers other than $each")
+		}
+		if items, ok = modifiers[0].Value.(bson.A); !ok {
+			return nil, fmt.Errorf("the argument to $each in $push must be an array")
+		}
+	}
+
+	values := lookup(doc, splitPath(field))
+	if len(values) == 0 {
+		return set(doc, splitPath(field), items)
+	}
+
+	existing, ok := values[0].(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("the field '%s' must be an array but is of type %T", field, values[0])
+	}
+
+	return set(doc, splitPath(field), append(append(bson.A{}, existing...), items...))
+}
+
+func currentDate(doc bson.D, field string, value interface{}, now time.Time) (bson.D, error) {
+	if spec, ok := value.(bson.D); ok {
+		switch t, _ := get(spec, "$type"); t {
+		case "date":
+			return set(doc, splitPath(field), primitive.NewDateTimeFromTime(now))
+		case "timestamp":
+			return set(doc, splitPath(field), primitive.Timestamp{T: uint32(now.Unix())})
+		default:
+			return nil, fmt.Errorf("the '$type' string field is required to be 'date' or 'timestamp'")
+		}
+	}
+
+	if _, ok := value.(bool); !ok {
+		return nil, fmt.Errorf("%s is not valid type for $currentDate", field)
+	}
+
+	return set(doc, splitPath(field), primitive.NewDateTimeFromTime(now))
+}
+
+// upsertDocument returns the document inserted by an upsert that matches no documents: the equality conditions of
+// the filter, with the update applied
+func upsertDocument(filter bson.D, update bson.D, now time.Time) (bson.D, error) {
+	doc := bson.D{}
+
+	var err error
+	for _, e := range filter {
+		if strings.HasPrefix(e.Key, "$") {
+			continue
+		}
+
+		value := e.Value
+		if operators, ok := value.(bson.D); ok && len(operators) > 0 && strings.HasPrefix(operators[0].Key, "$") {
+			eq, ok := get(operators, "$eq")
+			if !ok {
+				continue
+			}
+			value = eq
+		}
+
+		if doc, err = set(doc, splitPath(e.Key), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return applyUpdate(doc, update, now, true)
+}
@@ -0,0 +1,316 @@
+package mongotest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrUnsupported is returned when a query, update, projection or pipeline uses a feature that the in-memory
+// collection does not support
+var ErrUnsupported = errors.New("unsupported by the in-memory collection")
+
+func unsupported(feature string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, feature)
+}
+
+// toDocument returns the given document (e.g. a struct, bson.M or bson.D) as a bson.D, in which embedded documents
+// are bson.D and arrays bson.A, so that all values have the types used for comparisons
+func toDocument(v interface{}) (bson.D, error) {
+	if v == nil {
+		return bson.D{}, nil
+	}
+
+	b, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var d bson.D
+	if err = bson.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// toArray returns the given array (e.g. a bson.A, mongo.Pipeline or slice) as a bson.A, as for toDocument
+func toArray(v interface{}) (bson.A, error) {
+	d, err := toDocument(bson.D{{Key: "a", Value: v}})
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := d[0].Value.(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("expected an array but got %T", v)
+	}
+
+	return a, nil
+}
+
+// get returns the value of the key in the document
+func get(doc bson.D, key string) (interface{}, bool) {
+	for _, e := range doc {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookup returns the values at the dotted path in the given value, traversing any arrays on the path
+func lookup(v interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		return []interface{}{v}
+	}
+
+	switch t := v.(type) {
+	case bson.D:
+		if child, ok := get(t, path[0]); ok {
+			return lookup(child, path[1:])
+		}
+	case bson.A:
+		if i, err := strconv.Atoi(path[0]); err == nil {
+			if i < len(t) {
+				return lookup(t[i], path[1:])
+			}
+			return nil
+		}
+		var values []interface{}
+		for _, e := range t {
+			if d, ok := e.(bson.D); ok {
+				values = append(values, lookup(d, path)...)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// set returns the document with the value at the dotted path set, creating any missing embedded documents
+func set(doc bson.D, path []string, value interface{}) (bson.D, error) {
+	for i, e := range doc {
+		if e.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			doc[i].Value = value
+			return doc, nil
+		}
+		child, err := setIn(e.Value, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		doc[i].Value = child
+		return doc, nil
+	}
+
+	if len(path) == 1 {
+		return append(doc, bson.E{Key: path[0], Value: value}), nil
+	}
+	child, err := set(bson.D{}, path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(doc, bson.E{Key: path[0], Value: child}), nil
+}
+
+func setIn(v interface{}, path []string, value interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case bson.D:
+		return set(t, path, value)
+	case bson.A:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i >= len(t) {
+			return nil, fmt.Errorf("cannot create field '%s' in array", path[0])
+		}
+		if len(path) == 1 {
+			t[i] = value
+			return t, nil
+		}
+		child, err := setIn(t[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = child
+		return t, nil
+	default:
+		return nil, fmt.Errorf("cannot create field '%s' in element of type %T", path[0], v)
+	}
+}
+
+// unset returns the document with the value at the dotted path removed
+func unset(doc bson.D, path []string) bson.D {
+	for i, e := range doc {
+		if e.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return append(doc[:i:i], doc[i+1:]...)
+		}
+		if child, ok := e.Value.(bson.D); ok {
+			doc[i].Value = unset(child, path[1:])
+		}
+		return doc
+	}
+
+	return doc
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// typeOrder returns the position of the type of the value in the order in which MongoDB compares values of different
+// types. All numeric types have the same position
+func typeOrder(v interface{}) int {
+	switch v.(type) {
+	case primitive.MinKey:
+		return 0
+	case nil, primitive.Null, primitive.Undefined:
+		return 1
+	case int32, int64, float64, primitive.Decimal128:
+		return 2
+	case string, primitive.Symbol:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case primitive.Binary:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	case primitive.Timestamp:
+		return 10
+	case primitive.Regex:
+		return 11
+	case primitive.MaxKey:
+		return 13
+	default:
+		return 12
+	}
+}
+
+// compare returns -1, 0 or 1 as a is less than, equal to or greater than b, in the order in which MongoDB compares
+// values
+func compare(a, b interface{}) int {
+	oa, ob := typeOrder(a), typeOrder(b)
+	if oa != ob {
+		return sign(oa - ob)
+	}
+
+	switch x := a.(type) {
+	case int32, int64, float64, primitive.Decimal128:
+		fa, fb := toFloat(a), toFloat(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(x, fmt.Sprint(b))
+	case bson.D:
+		y := b.(bson.D)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := strings.Compare(x[i].Key, y[i].Key); c != 0 {
+				return c
+			}
+			if c := compare(x[i].Value, y[i].Value); c != 0 {
+				return c
+			}
+		}
+		return sign(len(x) - len(y))
+	case bson.A:
+		y := b.(bson.A)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compare(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return sign(len(x) - len(y))
+	case primitive.Binary:
+		return bytes.Compare(x.Data, b.(primitive.Binary).Data)
+	case primitive.ObjectID:
+		y := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:])
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case y:
+			return -1
+		}
+		return 1
+	case primitive.DateTime:
+		return sign64(int64(x) - int64(b.(primitive.DateTime)))
+	case primitive.Timestamp:
+		return primitive.CompareTimestamp(x, b.(primitive.Timestamp))
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return 0
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case primitive.Decimal128:
+		f, _ := strconv.ParseFloat(n.String(), 64)
+		return f
+	}
+
+	return 0
+}
+
+func sign(i int) int {
+	return sign64(int64(i))
+}
+
+func sign64(i int64) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+
+	return 0
+}
+
+// isTruthy returns whether a projection or $currentDate value is set
+func isTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int32, int64, float64:
+		return toFloat(t) != 0
+	}
+
+	return v != nil
+}
@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// CommandMonitorOption configures a command monitor
+type CommandMonitorOption func(*commandMonitor)
+
+var (
+	// LogSlowCommands logs a warning, through log.go, for each command that takes at least the given threshold, with
+	// the shapes of its filter and sort order. A threshold <=0 disables the logging
+	LogSlowCommands = func(threshold time.Duration) CommandMonitorOption {
+		return func(m *commandMonitor) { m.slowThreshold = threshold }
+	}
+)
+
+type commandMonitor struct {
+	slowThreshold time.Duration
+	commands      sync.Map
+}
+
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// startedCommand is a command that has been sent to the server, but for which no reply has yet been received
+type startedCommand struct {
+	span       trace.Span
+	database   string
+	collection string
+	command    bson.Raw
+}
+
+// NewCommandMonitor returns a driver command monitor that records a span for each command sent to the server, as a
+// child of the span in the context of the operation sending it, and optionally logs slow commands. Open sets it on the
+// connection's client; it should be set on any client created elsewhere and passed to NewMongoConnection
+func NewCommandMonitor(opts ...CommandMonitorOption) *event.CommandMonitor {
+	m := &commandMonitor{}
+	for _, o := range opts {
+		o(m)
+	}
+
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *commandMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	sc := &startedCommand{database: evt.DatabaseName}
+	sc.collection, _ = evt.Command.Lookup(evt.CommandName).StringValueOK()
+
+	attrs := []attribute.KeyValue{
+		dbSystemMongoDB,
+		dbNameKey.String(evt.DatabaseName),
+		dbSpanOperationKey.String(evt.CommandName),
+	}
+	if sc.collection != "" {
+		attrs = append(attrs, dbCollectionKey.String(sc.collection))
+	}
+	_, sc.span = getTracer().Start(ctx, "mongodb."+evt.CommandName,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	// The command is only kept (as a copy, since the driver may reuse its buffer) if it may need to be logged
+	if m.slowThreshold > 0 {
+		sc.command = append(bson.Raw(nil), evt.Command...)
+	}
+
+	m.commands.Store(commandKey{evt.ConnectionID, evt.RequestID}, sc)
+}
+
+func (m *commandMonitor) succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	if sc, ok := m.finished(ctx, &evt.CommandFinishedEvent); ok {
+		sc.span.End()
+	}
+}
+
+func (m *commandMonitor) failed(ctx context.Context, evt *event.CommandFailedEvent) {
+	if sc, ok := m.finished(ctx, &evt.CommandFinishedEvent); ok {
+		sc.span.SetStatus(codes.Error, evt.Failure)
+		sc.span.End()
+	}
+}
+
+// finished returns the started command for the finished event, logging it if it was slow
+// A getMore command is never logged, since one that waits for new data (e.g. for a change stream) is expected to be
+// slow; the time taken to iterate a cursor is instead included in the Collection operation that does so
+func (m *commandMonitor) finished(ctx context.Context, evt *event.CommandFinishedEvent) (*startedCommand, bool) {
+	v, ok := m.commands.LoadAndDelete(commandKey{evt.ConnectionID, evt.RequestID})
+	if !ok {
+		return nil, false
+	}
+	sc := v.(*startedCommand)
+
+	if m.slowThreshold > 0 && evt.Duration >= m.slowThreshold && evt.CommandName != "getMore" {
+		filter, sort := commandShapes(sc.command)
+		logSlow(ctx, "slow mongodb command", log.Data{
+			"command":    evt.CommandName,
+			"database":   sc.database,
+			"collection": sc.collection,
+		}, evt.Duration, m.slowThreshold, filter, sort)
+	}
+
+	return sc, true
+}
+
+// commandShapes returns the shapes of the filter (or pipeline) and sort order of a command
+func commandShapes(command bson.Raw) (filter, sort string) {
+	for _, key := range []string{"filter", "query", "pipeline"} {
+		if v, err := command.LookupErr(key); err == nil {
+			filter = rawShape(v)
+			break
+		}
+	}
+
+	// The filters of update and delete commands are given in the statements they contain
+	for _, key := range []string{"updates", "deletes"} {
+		statements, ok := command.Lookup(key).ArrayOK()
+		if !ok {
+			continue
+		}
+		values, _ := statements.Values()
+		shapes := make([]string, 0, len(values))
+		for _, v := range values {
+			if doc, ok := v.DocumentOK(); ok {
+				if q, err := doc.LookupErr("q"); err == nil {
+					shapes = append(shapes, rawShape(q))
+				}
+			}
+		}
+		filter = joinShapes(shapes)
+	}
+
+	if v, err := command.LookupErr("sort"); err == nil {
+		sort = rawShape(v)
+	}
+
+	return filter, sort
+}
+
+// logSlow logs a warning for an operation or command that took at least the threshold
+func logSlow(ctx context.Context, event string, data log.Data, duration, threshold time.Duration, filter, sort string) {
+	data["duration"] = duration.String()
+	data["threshold"] = threshold.String()
+	if filter != "" {
+		data["filter"] = filter
+	}
+	if sort != "" {
+		data["sort"] = sort
+	}
+
+	log.Warn(ctx, event, data)
+}
@@ -0,0 +1,150 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCommandShapes(t *testing.T) {
+	Convey("Given commands sent to the server", t, func() {
+		Convey("Then the filter and sort order of a find command are shaped", func() {
+			filter, sort := commandShapes(marshalCommand(bson.D{
+				{Key: "find", Value: "coll"},
+				{Key: "filter", Value: bson.M{"state": "published"}},
+				{Key: "sort", Value: bson.D{{Key: "last_updated", Value: -1}}},
+			}))
+			So(filter, ShouldEqual, `{"state": ?string}`)
+			So(sort, ShouldEqual, `{"last_updated": ?number}`)
+		})
+
+		Convey("Then the pipeline of an aggregate command is shaped", func() {
+			filter, sort := commandShapes(marshalCommand(bson.D{
+				{Key: "aggregate", Value: "coll"},
+				{Key: "pipeline", Value: bson.A{bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}}}},
+			}))
+			So(filter, ShouldEqual, `[{"$match": {"count": {"$gt": ?number}}}]`)
+			So(sort, ShouldEqual, "")
+		})
+
+		Convey("Then the filters of the statements of an update command are shaped", func() {
+			filter, _ := commandShapes(marshalCommand(bson.D{
+				{Key: "update", Value: "coll"},
+				{Key: "updates", Value: bson.A{
+					bson.M{"q": bson.M{"_id": 1}, "u": bson.M{"$set": bson.M{"state": "x"}}},
+					bson.M{"q": bson.M{"_id": 2}, "u": bson.M{"$set": bson.M{"state": "y"}}},
+				}},
+			}))
+			So(filter, ShouldEqual, `[{"_id": ?number}]`)
+		})
+	})
+}
+
+func TestSlowLogging(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	log.SetDestination(&buf, nil)
+	defer log.SetDestination(os.Stdout, os.Stderr)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	Convey("Given a command monitor logging slow commands", t, func() {
+		buf.Reset()
+		monitor := NewCommandMonitor(LogSlowCommands(100 * time.Millisecond))
+		command := marshalCommand(bson.D{{Key: "count", Value: "coll"}, {Key: "query", Value: bson.M{"secret": "value"}}})
+		started := func(requestID int64, commandName string) {
+			monitor.Started(ctx, &event.CommandStartedEvent{Command: command, DatabaseName: "db", CommandName: commandName, RequestID: requestID})
+		}
+		finished := func(requestID int64, commandName string, duration time.Duration) event.CommandFinishedEvent {
+			return event.CommandFinishedEvent{RequestID: requestID, CommandName: commandName, Duration: duration}
+		}
+
+		Convey("When a command finishes within the threshold", func() {
+			started(1, "count")
+			monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1, "count", 10*time.Millisecond)})
+
+			Convey("Then nothing is logged", func() {
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a command takes longer than the threshold", func() {
+			started(2, "count")
+			monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(2, "count", 150*time.Millisecond)})
+
+			Convey("Then the command is logged with the shape of its filter, but not its values", func() {
+				So(buf.String(), ShouldContainSubstring, `"event":"slow mongodb command"`)
+				So(buf.String(), ShouldContainSubstring, `"command":"count"`)
+				So(buf.String(), ShouldContainSubstring, `"collection":"coll"`)
+				So(buf.String(), ShouldContainSubstring, `"duration":"150ms"`)
+				So(buf.String(), ShouldContainSubstring, `"filter":"{\"secret\": ?string}"`)
+				So(buf.String(), ShouldNotContainSubstring, "value")
+			})
+		})
+
+		Convey("When a getMore command takes longer than the threshold", func() {
+			started(3, "getMore")
+			monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(3, "getMore", time.Second)})
+
+			Convey("Then nothing is logged", func() {
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a collection with a slow operation threshold", t, func() {
+		buf.Reset()
+		c := NewCollection(client.Database("db").Collection("coll"))
+		c.slowOperationThreshold = 10 * time.Millisecond
+
+		Convey("When an operation takes longer than the threshold", func() {
+			_, op := c.startOperation(ctx, "Find")
+			op.filter(bson.M{"state": "published"})
+			op.sort(bson.M{"_id": 1})
+			time.Sleep(20 * time.Millisecond)
+			var noErr error
+			op.end(&noErr)
+
+			Convey("Then the operation is logged with the shapes of its filter and sort order", func() {
+				So(buf.String(), ShouldContainSubstring, `"event":"slow mongodb operation"`)
+				So(buf.String(), ShouldContainSubstring, `"operation":"Find"`)
+				So(buf.String(), ShouldContainSubstring, `"database":"db"`)
+				So(buf.String(), ShouldContainSubstring, `"collection":"coll"`)
+				So(buf.String(), ShouldContainSubstring, `"threshold":"10ms"`)
+				So(buf.String(), ShouldContainSubstring, `"filter":"{\"state\": ?string}"`)
+				So(buf.String(), ShouldContainSubstring, `"sort":"{\"_id\": ?number}"`)
+			})
+		})
+
+		Convey("When an operation finishes within the threshold", func() {
+			_, op := c.startOperation(ctx, "Find")
+			var noErr error
+			op.end(&noErr)
+
+			Convey("Then nothing is logged", func() {
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func marshalCommand(command bson.D) bson.Raw {
+	b, err := bson.Marshal(command)
+	So(err, ShouldBeNil)
+	return b
+}
@@ -6,6 +6,23 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+//go:generate moq -out mock/must.go -pkg mock . MustAPI
+
+// MustAPI is the interface of the operations of a Must, which return an ErrNoDocumentFound error if no document is
+// matched
+type MustAPI interface {
+	UpdateById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error)
+	Update(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpdateOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	UpdateMany(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error)
+	DeleteById(ctx context.Context, id interface{}) (*CollectionDeleteResult, error)
+	Delete(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+	DeleteOne(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+	DeleteMany(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error)
+}
+
+var _ MustAPI = (*Must)(nil)
+
 type Must struct {
 	collection *Collection
 }
@@ -18,7 +35,7 @@ func newMust(collection *Collection) *Must {
 // If a  document cannot be found, an ErrNoDocumentFound error is returned
 // Deprecated: Use UpdateOne instead
 func (m *Must) UpdateById(ctx context.Context, id interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "must.UpdateById")
+	ctx, span := m.collection.startSpan(ctx, "must.UpdateById", "UpdateById")
 	defer span.End()
 	return m.UpdateOne(ctx, bson.M{"_id": id}, update)
 }
@@ -27,7 +44,7 @@ func (m *Must) UpdateById(ctx context.Context, id interface{}, update interface{
 // If a  document cannot be found, an ErrNoDocumentFound error is returned
 // Deprecated: Use UpdateOne instead
 func (m *Must) Update(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "must.Update")
+	ctx, span := m.collection.startSpan(ctx, "must.Update", "Update")
 	defer span.End()
 	return m.UpdateOne(ctx, selector, update)
 }
@@ -38,7 +55,7 @@ func (m *Must) Update(ctx context.Context, selector interface{}, update interfac
 // If the selector does not match any documents, an ErrNoDocumentFound is returned
 // If the selector matches multiple documents, one will be selected from the matched set, updated and a CollectionUpdateResult with a MatchedCount of 1 will be returned.
 func (m *Must) UpdateOne(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "must.UpdateOne")
+	ctx, span := m.collection.startSpan(ctx, "must.UpdateOne", "UpdateOne")
 	defer span.End()
 	result, err := m.collection.UpdateOne(ctx, selector, update)
 	if err != nil {
@@ -57,7 +74,7 @@ func (m *Must) UpdateOne(ctx context.Context, selector interface{}, update inter
 // The update must be a document containing update operators and cannot be nil or empty.
 // If the selector does not match any documents, an ErrNoDocumentFound is returned
 func (m *Must) UpdateMany(ctx context.Context, selector interface{}, update interface{}) (*CollectionUpdateResult, error) {
-	span := getSpan(ctx, "must.UpdateMany")
+	ctx, span := m.collection.startSpan(ctx, "must.UpdateMany", "UpdateMany")
 	defer span.End()
 	result, err := m.collection.UpdateMany(ctx, selector, update)
 	if err != nil {
@@ -75,7 +92,7 @@ func (m *Must) UpdateMany(ctx context.Context, selector interface{}, update inte
 // an ErrNoDocumentFound error is returned
 // Deprecated: Use DeleteOne
 func (m *Must) DeleteById(ctx context.Context, id interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "must.DeleteById")
+	ctx, span := m.collection.startSpan(ctx, "must.DeleteById", "DeleteById")
 	defer span.End()
 	return m.DeleteOne(ctx, bson.M{"_id": id})
 }
@@ -84,7 +101,7 @@ func (m *Must) DeleteById(ctx context.Context, id interface{}) (*CollectionDelet
 // an ErrNoDocumentFound error is returned
 // Deprecated: Use DeleteOne
 func (m *Must) Delete(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "must.Delete")
+	ctx, span := m.collection.startSpan(ctx, "must.Delete", "Delete")
 	defer span.End()
 	return m.DeleteOne(ctx, selector)
 }
@@ -94,7 +111,7 @@ func (m *Must) Delete(ctx context.Context, selector interface{}) (*CollectionDel
 // If the selector does not match any documents, an ErrNoDocumentFound error is returned
 // If the selector matches multiple documents, one will be selected from the matched set, deleted and a CollectionDeleteResult with a DeletedCount of 1 will be returned.
 func (m *Must) DeleteOne(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "must.DeleteOne")
+	ctx, span := m.collection.startSpan(ctx, "must.DeleteOne", "DeleteOne")
 	defer span.End()
 	result, err := m.collection.DeleteOne(ctx, selector)
 	if err != nil {
@@ -112,7 +129,7 @@ func (m *Must) DeleteOne(ctx context.Context, selector interface{}) (*Collection
 // The selector must be a document containing query operators and cannot be nil.
 // If the selector does not match any documents, an ErrNoDocumentFound error is returned
 func (m *Must) DeleteMany(ctx context.Context, selector interface{}) (*CollectionDeleteResult, error) {
-	span := getSpan(ctx, "must.DeleteMany")
+	ctx, span := m.collection.startSpan(ctx, "must.DeleteMany", "DeleteMany")
 	defer span.End()
 	result, err := m.collection.DeleteMany(ctx, selector)
 	if err != nil {
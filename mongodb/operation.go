@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// operation traces and records the metrics of a single Collection operation
+type operation struct {
+	ctx         context.Context
+	span        trace.Span
+	name        string
+	collection  *Collection
+	attributes  []attribute.KeyValue
+	filterValue interface{}
+	sortValue   interface{}
+	start       time.Time
+	returned    int
+	modified    int
+}
+
+// startOperation starts a span for the named operation on the collection, and starts recording its metrics, returning
+// the context carrying the span, which should be used for the rest of the operation
+func (c *Collection) startOperation(ctx context.Context, name string) (context.Context, *operation) {
+	ctx, span := c.startSpan(ctx, "collection."+name, name)
+
+	return ctx, &operation{
+		ctx:        ctx,
+		span:       span,
+		name:       name,
+		collection: c,
+		attributes: []attribute.KeyValue{
+			dbNamespaceKey.String(c.collection.Database().Name()),
+			dbCollectionKey.String(c.collection.Name()),
+			dbOperationKey.String(name),
+		},
+		start: time.Now(),
+	}
+}
+
+// filter sets the operation's filter (or pipeline), adding its shape to the span if tracing filter shapes is enabled
+func (o *operation) filter(filter interface{}) {
+	o.filterValue = filter
+	if o.collection.traceFilterShapes {
+		o.span.SetAttributes(dbStatementKey.String(filterShape(filter)))
+	}
+}
+
+// sort sets the operation's sort order
+func (o *operation) sort(sort interface{}) {
+	o.sortValue = sort
+}
+
+// end records the duration of the operation, the documents it returned and modified, and the class of the error
+// pointed to, if any, and ends its span. If the operation took at least the collection's slow operation threshold, a
+// warning is logged with the shapes of its filter and sort order
+func (o *operation) end(err *error) {
+	m := getInstruments()
+	attrs := metric.WithAttributes(o.attributes...)
+
+	duration := time.Since(o.start)
+	m.duration.Record(o.ctx, duration.Seconds(), attrs)
+	if o.returned > 0 {
+		m.returned.Add(o.ctx, int64(o.returned), attrs)
+	}
+	if o.modified > 0 {
+		m.modified.Add(o.ctx, int64(o.modified), attrs)
+	}
+
+	var e error
+	if err != nil {
+		e = *err
+	}
+	if e != nil {
+		m.errors.Add(o.ctx, 1, metric.WithAttributes(append(o.attributes, errorTypeKey.String(errorClass(e)))...))
+	}
+
+	if threshold := o.collection.slowOperationThreshold; threshold > 0 && duration >= threshold {
+		logSlow(o.ctx, "slow mongodb operation", log.Data{
+			"operation":  o.name,
+			"database":   o.collection.collection.Database().Name(),
+			"collection": o.collection.collection.Name(),
+		}, duration, threshold, filterShape(o.filterValue), filterShape(o.sortValue))
+	}
+
+	endSpan(o.span, e)
+}
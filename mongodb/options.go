@@ -18,16 +18,18 @@ var (
 	Projection     = func(p interface{}) FindOption { return func(f *findOptions) { f.projection = p } }
 	ReturnDocument = func(when options.ReturnDocument) FindOption { return func(f *findOptions) { f.returnDocument = when } }
 
-	IgnoreZeroLimit = func() FindOption { return func(f *findOptions) { f.obeyZeroLimit = false } }
+	IgnoreZeroLimit   = func() FindOption { return func(f *findOptions) { f.obeyZeroLimit = false } }
+	WithoutTotalCount = func() FindOption { return func(f *findOptions) { f.withoutTotalCount = true } }
 )
 
 type findOptions struct {
-	limit          int64
-	returnDocument options.ReturnDocument
-	skip           int64
-	sort           interface{}
-	projection     interface{}
-	obeyZeroLimit  bool
+	limit             int64
+	returnDocument    options.ReturnDocument
+	skip              int64
+	sort              interface{}
+	projection        interface{}
+	obeyZeroLimit     bool
+	withoutTotalCount bool
 }
 
 func newFindOptions(opts ...FindOption) *findOptions {
@@ -39,6 +41,32 @@ func newFindOptions(opts ...FindOption) *findOptions {
 	return f
 }
 
+// FindOptions are the settings given by a list of FindOption, for use by alternative implementations of CollectionAPI
+type FindOptions struct {
+	Sort              interface{}
+	Projection        interface{}
+	Skip              int64
+	Limit             int64
+	ReturnDocument    options.ReturnDocument
+	ObeyZeroLimit     bool // Whether a Limit of 0 returns no documents, rather than all documents
+	WithoutTotalCount bool
+}
+
+// ResolveFindOptions returns the settings given by the list of options
+func ResolveFindOptions(opts ...FindOption) FindOptions {
+	fo := newFindOptions(opts...)
+
+	return FindOptions{
+		Sort:              fo.sort,
+		Projection:        fo.projection,
+		Skip:              fo.skip,
+		Limit:             fo.limit,
+		ReturnDocument:    fo.returnDocument,
+		ObeyZeroLimit:     fo.obeyZeroLimit,
+		WithoutTotalCount: fo.withoutTotalCount,
+	}
+}
+
 func (fo findOptions) asDriverFindOption() *options.FindOptions {
 	return options.Find().SetSort(fo.sort).SetSkip(fo.skip).SetLimit(fo.limit).SetProjection(fo.projection)
 }
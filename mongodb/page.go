@@ -0,0 +1,309 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidPageLimit = errors.New("a positive limit must be provided for a paged find")
+	ErrInvalidPageSort  = errors.New("page sort order must be a bson.D, or a bson.M with a single key")
+
+	// ErrInvalidPageSortValue is returned by FindPage if the value of a sort key in the last document of a page is an
+	// array, or of a deprecated BSON type, or, if a Projection is given, a returned document has no value for a sort
+	// key, so that the following page cannot be found
+	ErrInvalidPageSortValue = errors.New("page sort key value must be returned, and not be an array or of a deprecated type")
+)
+
+// sortTypeOrder gives the $type aliases of the BSON types, grouped and in the order in which MongoDB sorts values of
+// different types; the types within a group compare by value. Arrays are sorted by their elements, so are not included
+var sortTypeOrder = [][]string{
+	{"minKey"}, {"null"}, {"double", "int", "long", "decimal"}, {"symbol", "string"}, {"object"}, {"binData"},
+	{"objectId"}, {"bool"}, {"date"}, {"timestamp"}, {"regex"}, {"maxKey"},
+}
+
+// sortTypeGroups maps each BSON type that can be used in a page token to its group in sortTypeOrder
+var sortTypeGroups = map[bsontype.Type]int{
+	bsontype.MinKey: 0, bsontype.Null: 1,
+	bsontype.Double: 2, bsontype.Int32: 2, bsontype.Int64: 2, bsontype.Decimal128: 2,
+	bsontype.Symbol: 3, bsontype.String: 3, bsontype.EmbeddedDocument: 4, bsontype.Binary: 5, bsontype.ObjectID: 6,
+	bsontype.Boolean: 7, bsontype.DateTime: 8, bsontype.Timestamp: 9, bsontype.Regex: 10, bsontype.MaxKey: 11,
+}
+
+const nullTypeGroup = 1
+
+// CollectionPage is the result type returned from FindPage operations.
+type CollectionPage struct {
+	TotalCount int    // The total number of documents that satisfy the filter, or -1 if WithoutTotalCount was given
+	NextToken  string // The token to pass to FindPage to obtain the next page, or empty if this is the last page
+}
+
+// pageToken is the decoded form of the opaque continuation token, holding the sort keys the token was created for,
+// and the values of those keys in the last document of the previous page
+type pageToken struct {
+	Keys   []string        `bson:"k"`
+	Values []bson.RawValue `bson:"v"`
+}
+
+// FindPage returns a page of the documents in the collection that satisfy the given filter, using keyset (cursor-based)
+// pagination, with the actual documents provided in the results parameter (which must be a non nil pointer to a slice
+// of the expected document type)
+// The page starts after the document identified by the given token; an empty token returns the first page. The token
+// for the following page is returned in the NextToken field of the CollectionPage, and is empty when there are no more
+// documents. A token can only be used with the same filter and sort order that produced it
+// The Limit option (which must be positive) gives the page size, and the Sort option gives the keyset, which must be
+// a bson.D or a single key bson.M; the sort keys must not hold arrays. As in MongoDB's sort order, a missing sort key
+// is treated as null, and values of different types are ordered by type. If a Projection is given, every returned
+// document must have a value for each sort key (including _id), so that a key excluded by the projection is not taken
+// to be null; otherwise ErrInvalidPageSortValue is returned.
+// If no sort order option is provided a default sort order of 'ascending _id' is used, and _id is always added as the
+// final sort key (if not already present) so that the order is total. The Offset option is ignored
+// Unless the WithoutTotalCount option is given, the total number of documents that satisfy the filter is also returned,
+// queried concurrently with the page as for Find
+func (c *Collection) FindPage(ctx context.Context, filter, results interface{}, token string, opts ...FindOption) (_ *CollectionPage, err error) {
+	ctx, op := c.startOperation(ctx, "FindPage")
+	defer op.end(&err)
+	op.filter(filter)
+
+	ctx, cancel := c.queryContext(ctx)
+	defer cancel()
+
+	fo := newFindOptions(opts...)
+	if fo.limit <= 0 {
+		return nil, ErrInvalidPageLimit
+	}
+
+	sort, err := pageSort(fo.sort)
+	if err != nil {
+		return nil, err
+	}
+	op.sort(sort)
+
+	pageFilter := filter
+	if token != "" {
+		pt, err := decodePageToken(token, sort)
+		if err != nil {
+			return nil, err
+		}
+		pageFilter = bson.M{"$and": bson.A{filter, pt.filter(sort)}}
+	}
+
+	totalCount := c.countDocuments(ctx, op, filter, fo)
+
+	findOpts := options.Find().SetSort(sort).SetLimit(fo.limit + 1).SetProjection(fo.projection)
+	var docs []bson.Raw
+	err = op.retry(ctx, true, func() error {
+		cursor, err := c.collection.Find(ctx, pageFilter, findOpts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &docs)
+	})
+	if err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	page := &CollectionPage{}
+	if page.TotalCount, err = totalCount(); err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	more := int64(len(docs)) > fo.limit
+	if more {
+		docs = docs[:fo.limit]
+	}
+	if fo.projection != nil {
+		if err = checkProjectedSortKeys(docs, sort); err != nil {
+			return nil, err
+		}
+	}
+	if more {
+		if page.NextToken, err = newPageToken(docs[len(docs)-1], sort); err != nil {
+			return nil, err
+		}
+	}
+	op.returned = len(docs)
+
+	return page, wrapMongoError(decodeDocuments(docs, results))
+}
+
+// pageSort returns the given sort order as a bson.D, defaulting to ascending _id, and with _id added as the final key
+// if not already present
+func pageSort(sort interface{}) (bson.D, error) {
+	var d bson.D
+
+	switch s := sort.(type) {
+	case nil:
+	case bson.D:
+		d = append(d, s...)
+	case bson.M:
+		if len(s) > 1 {
+			return nil, ErrInvalidPageSort
+		}
+		for k, v := range s {
+			d = append(d, bson.E{Key: k, Value: v})
+		}
+	default:
+		return nil, ErrInvalidPageSort
+	}
+
+	for _, e := range d {
+		if e.Key == "_id" {
+			return d, nil
+		}
+	}
+
+	return append(d, bson.E{Key: "_id", Value: 1}), nil
+}
+
+// checkProjectedSortKeys returns an ErrInvalidPageSortValue error if any of the documents, returned with a projection,
+// has no value for a sort key, which the projection may have excluded
+func checkProjectedSortKeys(docs []bson.Raw, sort bson.D) error {
+	for _, doc := range docs {
+		for _, e := range sort {
+			if _, err := doc.LookupErr(strings.Split(e.Key, ".")...); err != nil {
+				return fmt.Errorf("%w: sort key %s is missing from a document, and may be excluded by the projection", ErrInvalidPageSortValue, e.Key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newPageToken creates the token identifying the position after the given document in the given sort order
+// A sort key missing from the document is given a null value, with which it sorts
+func newPageToken(doc bson.Raw, sort bson.D) (string, error) {
+	pt := pageToken{}
+	for _, e := range sort {
+		v, err := doc.LookupErr(strings.Split(e.Key, ".")...)
+		if err != nil {
+			v = bson.RawValue{Type: bsontype.Null}
+		}
+		if _, ok := sortTypeGroups[v.Type]; !ok {
+			return "", fmt.Errorf("%w: sort key %s is of type %s", ErrInvalidPageSortValue, e.Key, v.Type)
+		}
+		pt.Keys = append(pt.Keys, e.Key)
+		pt.Values = append(pt.Values, v)
+	}
+
+	b, err := bson.Marshal(pt)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodePageToken decodes the given token, checking it was created for the given sort order
+func decodePageToken(token string, sort bson.D) (*pageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	pt := &pageToken{}
+	if err = bson.Unmarshal(b, pt); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	if len(pt.Keys) != len(sort) || len(pt.Values) != len(sort) {
+		return nil, ErrInvalidPageToken
+	}
+	for i, e := range sort {
+		if pt.Keys[i] != e.Key {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	return pt, nil
+}
+
+// filter returns the query selecting the documents that follow the token's position in the given sort order, i.e.
+// {$or: [{k1: {$gt: v1}}, {k1: v1, k2: {$gt: v2}}, ...]}, with $lt used in place of $gt for descending keys, and with
+// further clauses for the values of the types that sort after each value
+func (pt *pageToken) filter(sort bson.D) bson.M {
+	clauses := bson.A{}
+	for i, e := range sort {
+		prefix := bson.D{}
+		for j := 0; j < i; j++ {
+			prefix = append(prefix, bson.E{Key: sort[j].Key, Value: pt.Values[j]})
+		}
+
+		for _, cond := range followingValues(e.Key, pt.Values[i], isDescending(e.Value)) {
+			clauses = append(clauses, append(append(bson.D{}, prefix...), cond))
+		}
+	}
+
+	return bson.M{"$or": clauses}
+}
+
+// followingValues returns the conditions on the key that select the values after v in the sort order: the greater (or
+// lesser, if descending) values of the same type group, as $gt and $lt only compare values of the same type, then the
+// values of the type groups that sort after (or before) it. Null values and missing keys are selected by {key: null}
+func followingValues(key string, v bson.RawValue, descending bool) []bson.E {
+	group := sortTypeGroups[v.Type]
+	op := "$gt"
+	if descending {
+		op = "$lt"
+	}
+
+	var conds []bson.E
+	// null, minKey and maxKey have no other values of their type group
+	if v.Type != bsontype.Null && v.Type != bsontype.MinKey && v.Type != bsontype.MaxKey {
+		conds = append(conds, bson.E{Key: key, Value: bson.M{op: v}})
+	}
+
+	types := bson.A{}
+	for g := range sortTypeOrder {
+		if descending && g >= group || !descending && g <= group {
+			continue
+		}
+		if g == nullTypeGroup {
+			conds = append(conds, bson.E{Key: key, Value: nil})
+			continue
+		}
+		for _, t := range sortTypeOrder[g] {
+			types = append(types, t)
+		}
+	}
+	if len(types) > 0 {
+		conds = append(conds, bson.E{Key: key, Value: bson.M{"$type": types}})
+	}
+
+	return conds
+}
+
+func isDescending(direction interface{}) bool {
+	d, ok := asFloat(direction)
+	return ok && d < 0
+}
+
+// decodeDocuments decodes the given documents into results, which must be a non nil pointer to a slice
+func decodeDocuments(docs []bson.Raw, results interface{}) error {
+	rv := reflect.ValueOf(results)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("results argument must be a pointer to a slice")
+	}
+
+	sv := rv.Elem()
+	slice := reflect.MakeSlice(sv.Type(), 0, len(docs))
+	for _, d := range docs {
+		ev := reflect.New(sv.Type().Elem())
+		if err := bson.Unmarshal(d, ev.Interface()); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, ev.Elem())
+	}
+	sv.Set(slice)
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type pageTestModel struct {
+	ID    int    `bson:"_id"`
+	State string `bson:"state"`
+}
+
+func TestPageSort(t *testing.T) {
+	Convey("pageSort defaults to ascending _id", t, func() {
+		s, err := pageSort(nil)
+		So(err, ShouldBeNil)
+		So(s, ShouldResemble, bson.D{{Key: "_id", Value: 1}})
+	})
+
+	Convey("pageSort adds _id as the final sort key of a bson.D if not already present", t, func() {
+		s, err := pageSort(bson.D{{Key: "state", Value: -1}})
+		So(err, ShouldBeNil)
+		So(s, ShouldResemble, bson.D{{Key: "state", Value: -1}, {Key: "_id", Value: 1}})
+
+		s, err = pageSort(bson.D{{Key: "_id", Value: -1}, {Key: "state", Value: 1}})
+		So(err, ShouldBeNil)
+		So(s, ShouldResemble, bson.D{{Key: "_id", Value: -1}, {Key: "state", Value: 1}})
+	})
+
+	Convey("pageSort accepts a single key bson.M", t, func() {
+		s, err := pageSort(bson.M{"state": 1})
+		So(err, ShouldBeNil)
+		So(s, ShouldResemble, bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}})
+	})
+
+	Convey("pageSort rejects a multiple key bson.M, which has no defined order", t, func() {
+		_, err := pageSort(bson.M{"state": 1, "_id": 1})
+		So(err, ShouldEqual, ErrInvalidPageSort)
+	})
+}
+
+func TestPageToken(t *testing.T) {
+	Convey("Given a document and a sort order", t, func() {
+		doc, err := bson.Marshal(pageTestModel{ID: 3, State: "first"})
+		So(err, ShouldBeNil)
+		sort := bson.D{{Key: "state", Value: -1}, {Key: "_id", Value: 1}}
+
+		Convey("A token created from the document can be decoded with the same sort order", func() {
+			token, err := newPageToken(doc, sort)
+			So(err, ShouldBeNil)
+			So(token, ShouldNotBeEmpty)
+
+			pt, err := decodePageToken(token, sort)
+			So(err, ShouldBeNil)
+			So(pt.Keys, ShouldResemble, []string{"state", "_id"})
+			So(pt.Values[0].StringValue(), ShouldEqual, "first")
+			So(pt.Values[1].Int32(), ShouldEqual, 3)
+
+			Convey("and gives a filter selecting the documents after the document in the sort order", func() {
+				So(pt.filter(sort), ShouldResemble, bson.M{"$or": bson.A{
+					bson.D{{Key: "state", Value: bson.M{"$lt": pt.Values[0]}}},
+					bson.D{{Key: "state", Value: nil}},
+					bson.D{{Key: "state", Value: bson.M{"$type": bson.A{"minKey", "double", "int", "long", "decimal"}}}},
+					bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$gt": pt.Values[1]}}},
+					bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$type": bson.A{
+						"symbol", "string", "object", "binData", "objectId", "bool", "date", "timestamp", "regex", "maxKey",
+					}}}},
+				}})
+			})
+		})
+
+		Convey("A token cannot be decoded with a different sort order", func() {
+			token, err := newPageToken(doc, sort)
+			So(err, ShouldBeNil)
+
+			_, err = decodePageToken(token, bson.D{{Key: "_id", Value: 1}})
+			So(err, ShouldEqual, ErrInvalidPageToken)
+		})
+
+		Convey("A token created with a sort key missing from the document gives the key a null value", func() {
+			token, err := newPageToken(doc, bson.D{{Key: "missing", Value: 1}})
+			So(err, ShouldBeNil)
+
+			pt, err := decodePageToken(token, bson.D{{Key: "missing", Value: 1}})
+			So(err, ShouldBeNil)
+			So(pt.Values[0].Type, ShouldEqual, bsontype.Null)
+		})
+
+		Convey("A token cannot be created if a sort key is an array", func() {
+			doc, err := bson.Marshal(bson.M{"_id": 1, "tags": bson.A{"a", "b"}})
+			So(err, ShouldBeNil)
+
+			_, err = newPageToken(doc, bson.D{{Key: "tags", Value: 1}})
+			So(errors.Is(err, ErrInvalidPageSortValue), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a page boundary on a null sort key value", t, func() {
+		doc, err := bson.Marshal(bson.M{"_id": 3, "state": nil})
+		So(err, ShouldBeNil)
+
+		Convey("An ascending filter selects the null or missing values with a greater _id, and all values of other types", func() {
+			sort := bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}}
+			pt := pageTokenFor(doc, sort)
+
+			So(pt.filter(sort), ShouldResemble, bson.M{"$or": bson.A{
+				bson.D{{Key: "state", Value: bson.M{"$type": bson.A{
+					"double", "int", "long", "decimal", "symbol", "string", "object", "binData", "objectId", "bool", "date",
+					"timestamp", "regex", "maxKey",
+				}}}},
+				bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$gt": pt.Values[1]}}},
+				bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$type": bson.A{
+					"symbol", "string", "object", "binData", "objectId", "bool", "date", "timestamp", "regex", "maxKey",
+				}}}},
+			}})
+		})
+
+		Convey("A descending filter selects the null or missing values with a greater _id, and only minKey values otherwise", func() {
+			sort := bson.D{{Key: "state", Value: -1}, {Key: "_id", Value: 1}}
+			pt := pageTokenFor(doc, sort)
+
+			So(pt.filter(sort)["$or"], ShouldResemble, bson.A{
+				bson.D{{Key: "state", Value: bson.M{"$type": bson.A{"minKey"}}}},
+				bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$gt": pt.Values[1]}}},
+				bson.D{{Key: "state", Value: pt.Values[0]}, {Key: "_id", Value: bson.M{"$type": bson.A{
+					"symbol", "string", "object", "binData", "objectId", "bool", "date", "timestamp", "regex", "maxKey",
+				}}}},
+			})
+		})
+	})
+
+	Convey("Given a page boundary on a numeric sort key value, where other documents hold strings or nulls", t, func() {
+		doc, err := bson.Marshal(bson.M{"_id": 3, "state": 2.5})
+		So(err, ShouldBeNil)
+
+		Convey("An ascending filter selects the greater numbers, and the strings and other later types", func() {
+			sort := bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}}
+			pt := pageTokenFor(doc, sort)
+
+			clauses := pt.filter(sort)["$or"].(bson.A)
+			So(clauses[0], ShouldResemble, bson.D{{Key: "state", Value: bson.M{"$gt": pt.Values[0]}}})
+			So(clauses[1], ShouldResemble, bson.D{{Key: "state", Value: bson.M{"$type": bson.A{
+				"symbol", "string", "object", "binData", "objectId", "bool", "date", "timestamp", "regex", "maxKey",
+			}}}})
+			So(clauses, ShouldNotContain, bson.D{{Key: "state", Value: nil}})
+		})
+
+		Convey("A descending filter selects the lesser numbers, and the nulls, missing keys and minKeys", func() {
+			sort := bson.D{{Key: "state", Value: -1}, {Key: "_id", Value: 1}}
+			pt := pageTokenFor(doc, sort)
+
+			clauses := pt.filter(sort)["$or"].(bson.A)
+			So(clauses[0], ShouldResemble, bson.D{{Key: "state", Value: bson.M{"$lt": pt.Values[0]}}})
+			So(clauses[1], ShouldResemble, bson.D{{Key: "state", Value: nil}})
+			So(clauses[2], ShouldResemble, bson.D{{Key: "state", Value: bson.M{"$type": bson.A{"minKey"}}}})
+		})
+	})
+
+	Convey("A corrupt token cannot be decoded", t, func() {
+		_, err := decodePageToken("not-a-token!", bson.D{{Key: "_id", Value: 1}})
+		So(err, ShouldEqual, ErrInvalidPageToken)
+	})
+}
+
+func TestCheckProjectedSortKeys(t *testing.T) {
+	Convey("Given the documents returned with Projection(bson.M{\"_id\": 0})", t, func() {
+		fo := newFindOptions(Projection(bson.M{"_id": 0}))
+		So(fo.projection, ShouldResemble, bson.M{"_id": 0})
+
+		first, err := bson.Marshal(bson.M{"state": "first"})
+		So(err, ShouldBeNil)
+		second, err := bson.Marshal(bson.M{"state": "second"})
+		So(err, ShouldBeNil)
+		docs := []bson.Raw{first, second}
+
+		Convey("The missing _id tiebreaker is reported, rather than taken to be null", func() {
+			err := checkProjectedSortKeys(docs, bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}})
+			So(errors.Is(err, ErrInvalidPageSortValue), ShouldBeTrue)
+		})
+
+		Convey("The documents are accepted if they have all the sort keys", func() {
+			So(checkProjectedSortKeys(docs, bson.D{{Key: "state", Value: 1}}), ShouldBeNil)
+		})
+	})
+}
+
+// pageTokenFor returns the decoded page token for the document and sort order
+func pageTokenFor(doc bson.Raw, sort bson.D) *pageToken {
+	token, err := newPageToken(doc, sort)
+	So(err, ShouldBeNil)
+	pt, err := decodePageToken(token, sort)
+	So(err, ShouldBeNil)
+	return pt
+}
+
+func TestDecodeDocuments(t *testing.T) {
+	Convey("decodeDocuments decodes raw documents into the given slice", t, func() {
+		d1, _ := bson.Marshal(pageTestModel{ID: 1, State: "first"})
+		d2, _ := bson.Marshal(pageTestModel{ID: 2, State: "second"})
+
+		var res []pageTestModel
+		So(decodeDocuments([]bson.Raw{d1, d2}, &res), ShouldBeNil)
+		So(res, ShouldResemble, []pageTestModel{{ID: 1, State: "first"}, {ID: 2, State: "second"}})
+	})
+
+	Convey("decodeDocuments returns an error if results is not a pointer to a slice", t, func() {
+		var res pageTestModel
+		So(decodeDocuments(nil, &res), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultResumeTokenCollection is the conventional name of the collection in which change stream resume tokens are
+// saved
+const DefaultResumeTokenCollection = "change_stream_checkpoints"
+
+// ResumeTokenCheckpoint is the document saved for each change stream consumer
+type ResumeTokenCheckpoint struct {
+	Consumer    string    `bson:"_id"`
+	ResumeToken bson.Raw  `bson:"resume_token"`
+	LastUpdated time.Time `bson:"last_updated,omitempty"`
+}
+
+// CollectionResumeTokenStore is a ResumeTokenStore that saves the latest resume token for each consumer as a
+// document, keyed by the consumer name, in a dedicated collection
+type CollectionResumeTokenStore struct {
+	collection CollectionAPI
+}
+
+// NewCollectionResumeTokenStore creates a new resume token store that saves tokens in the given collection
+func NewCollectionResumeTokenStore(collection CollectionAPI) *CollectionResumeTokenStore {
+	return &CollectionResumeTokenStore{collection}
+}
+
+// LoadResumeToken returns the last saved resume token for the consumer, or nil if no token has been saved
+func (s *CollectionResumeTokenStore) LoadResumeToken(ctx context.Context, consumer string) (bson.Raw, error) {
+	var checkpoint ResumeTokenCheckpoint
+
+	err := s.collection.FindOne(ctx, bson.M{"_id": consumer}, &checkpoint)
+	if errors.Is(err, ErrNoDocumentFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoint.ResumeToken, nil
+}
+
+// SaveResumeToken saves the resume token for the consumer, replacing any previously saved token
+func (s *CollectionResumeTokenStore) SaveResumeToken(ctx context.Context, consumer string, token bson.Raw) error {
+	update, err := WithLastUpdatedUpdate(bson.M{"$set": bson.M{"resume_token": token}})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.collection.UpsertOne(ctx, bson.M{"_id": consumer}, update)
+	return err
+}
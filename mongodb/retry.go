@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+
+	retryableWriteErrorLabel = "RetryableWriteError"
+)
+
+// retryableCodes are the codes of the server errors after which an operation may succeed if retried, as they are
+// caused by a change in the replica set's state, such as the primary stepping down
+var retryableCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// retryPolicy configures the retrying of Collection operations that fail with a transient error
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// delay returns the backoff before the given retry (counting from 0): the initial backoff doubled for each previous
+// retry, up to the maximum backoff, of which a random amount of up to half is taken off
+func (p retryPolicy) delay(retry int) time.Duration {
+	backoff, maxBackoff := p.backoff, p.maxBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	d := backoff
+	for i := 0; i < retry && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	return d - rand.N(d/2+1)
+}
+
+type retryableWritesKey struct{}
+
+// WithRetryableWrites returns a copy of the given context that marks any Collection (or Must) write operation called
+// with it as safe to retry, if retries are configured, i.e. the write has the same effect whether it is applied once
+// or several times (as, for example, an update that only sets fields)
+// Read operations are always retried, since they are idempotent
+func WithRetryableWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableWritesKey{}, true)
+}
+
+func isRetryableWrite(ctx context.Context) bool {
+	retryable, _ := ctx.Value(retryableWritesKey{}).(bool)
+	return retryable
+}
+
+// isReadOnlyPipeline returns true if the aggregation pipeline does not write its output to a collection
+func isReadOnlyPipeline(pipeline interface{}) bool {
+	b, err := bson.Marshal(bson.D{{Key: "pipeline", Value: pipeline}})
+	if err != nil {
+		return false
+	}
+
+	stages, err := bson.Raw(b).Lookup("pipeline").Array().Values()
+	if err != nil {
+		return false
+	}
+	for _, s := range stages {
+		doc, ok := s.DocumentOK()
+		if !ok {
+			return false
+		}
+		if _, err = doc.LookupErr("$out"); err == nil {
+			return false
+		}
+		if _, err = doc.LookupErr("$merge"); err == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// retry calls fn, and calls it again, after a backoff, each time it fails with a transient error, up to the maximum
+// number of retries of the collection's retry policy. Only idempotent operations, and writes called with a context
+// marked by WithRetryableWrites, are retried; operations in a transaction are not, as the transaction must be retried
+// as a whole
+func (o *operation) retry(ctx context.Context, idempotent bool, fn func() error) error {
+	policy := o.collection.retryPolicy
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= policy.maxRetries || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if !idempotent && !isRetryableWrite(ctx) {
+			return err
+		}
+		if mongo.SessionFromContext(ctx) != nil {
+			return err
+		}
+
+		delay := policy.delay(attempt)
+		o.retried(attempt+1, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retried logs and counts a retry of the operation after the given error, and adds an event to its span
+func (o *operation) retried(retry int, delay time.Duration, err error) {
+	getInstruments().retries.Add(o.ctx, 1, metric.WithAttributes(append(o.attributes, errorTypeKey.String(errorClass(err)))...))
+
+	o.span.AddEvent("retry", trace.WithAttributes(attribute.Int("retry", retry), attribute.String("error", err.Error())))
+
+	log.Warn(o.ctx, "retrying mongodb operation", log.Data{
+		"operation":  o.name,
+		"database":   o.collection.collection.Database().Name(),
+		"collection": o.collection.collection.Name(),
+		"retry":      retry,
+		"backoff":    delay.String(),
+		"error":      err.Error(),
+	})
+}
@@ -0,0 +1,174 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIsRetryable(t *testing.T) {
+	Convey("Given errors returned by operations", t, func() {
+		Convey("Then network errors, retryable write errors and replica set state changes are retryable", func() {
+			So(IsRetryable(mongo.CommandError{Labels: []string{"NetworkError"}}), ShouldBeTrue)
+			So(IsRetryable(mongo.CommandError{Labels: []string{retryableWriteErrorLabel}}), ShouldBeTrue)
+			So(IsRetryable(Error{inner: mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}}), ShouldBeTrue)
+			So(IsRetryable(mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 189}}), ShouldBeTrue)
+		})
+
+		Convey("Then other errors are not retryable", func() {
+			So(IsRetryable(nil), ShouldBeFalse)
+			So(IsRetryable(ErrNoDocumentFound), ShouldBeFalse)
+			So(IsRetryable(context.DeadlineExceeded), ShouldBeFalse)
+			So(IsRetryable(mongo.CommandError{Code: 11000}), ShouldBeFalse)
+			So(IsRetryable(errors.New("decode failure")), ShouldBeFalse)
+		})
+	})
+}
+
+func TestIsReadOnlyPipeline(t *testing.T) {
+	Convey("Given aggregation pipelines", t, func() {
+		Convey("Then pipelines without $out or $merge stages are read only", func() {
+			So(isReadOnlyPipeline(bson.A{bson.M{"$match": bson.M{"state": "first"}}, bson.M{"$count": "n"}}), ShouldBeTrue)
+			So(isReadOnlyPipeline(mongo.Pipeline{{{Key: "$sort", Value: bson.M{"_id": 1}}}}), ShouldBeTrue)
+		})
+
+		Convey("Then pipelines with $out or $merge stages are not read only", func() {
+			So(isReadOnlyPipeline(bson.A{bson.M{"$match": bson.M{}}, bson.M{"$out": "other"}}), ShouldBeFalse)
+			So(isReadOnlyPipeline(bson.A{bson.M{"$merge": bson.M{"into": "other"}}}), ShouldBeFalse)
+		})
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	Convey("Given a retry policy", t, func() {
+		policy := retryPolicy{maxRetries: 10, backoff: 100 * time.Millisecond, maxBackoff: time.Second}
+
+		Convey("Then the backoff doubles for each retry, with up to half taken off by jitter, up to the maximum", func() {
+			for i := 0; i < 20; i++ {
+				So(policy.delay(0), ShouldBeBetweenOrEqual, 50*time.Millisecond, 100*time.Millisecond)
+				So(policy.delay(2), ShouldBeBetweenOrEqual, 200*time.Millisecond, 400*time.Millisecond)
+				So(policy.delay(50), ShouldBeBetweenOrEqual, 500*time.Millisecond, time.Second)
+			}
+		})
+
+		Convey("Then the default backoffs are used if none are configured", func() {
+			policy = retryPolicy{maxRetries: 1}
+			So(policy.delay(0), ShouldBeBetweenOrEqual, defaultRetryBackoff/2, defaultRetryBackoff)
+			So(policy.delay(50), ShouldBeBetweenOrEqual, defaultRetryMaxBackoff/2, defaultRetryMaxBackoff)
+		})
+	})
+}
+
+func TestOperationRetry(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	log.SetDestination(&buf, nil)
+	defer log.SetDestination(os.Stdout, os.Stderr)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	transient := mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}
+
+	Convey("Given a collection with a retry policy", t, func() {
+		buf.Reset()
+		c := NewCollection(client.Database("test-db").Collection("test-collection"))
+		c.retryPolicy = retryPolicy{maxRetries: 2, backoff: time.Millisecond}
+		_, op := c.startOperation(ctx, "Find")
+
+		calls := 0
+		failing := func(errs ...error) func() error {
+			return func() error {
+				calls++
+				if calls <= len(errs) {
+					return errs[calls-1]
+				}
+				return nil
+			}
+		}
+
+		Convey("When an idempotent operation fails with a transient error and then succeeds", func() {
+			err := op.retry(ctx, true, failing(transient))
+
+			Convey("Then it is retried and succeeds, and the retry is logged", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 2)
+				So(buf.String(), ShouldContainSubstring, `"event":"retrying mongodb operation"`)
+				So(buf.String(), ShouldContainSubstring, `"retry":1`)
+			})
+		})
+
+		Convey("When an idempotent operation keeps failing with a transient error", func() {
+			err := op.retry(ctx, true, failing(transient, transient, transient, transient))
+
+			Convey("Then it is retried up to the maximum number of retries, and the last error is returned", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When an operation fails with an error that is not transient", func() {
+			err := op.retry(ctx, true, failing(ErrNoDocumentFound))
+
+			Convey("Then it is not retried", func() {
+				So(err, ShouldEqual, ErrNoDocumentFound)
+				So(calls, ShouldEqual, 1)
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a write fails with a transient error", func() {
+			err := op.retry(ctx, false, failing(transient))
+
+			Convey("Then it is not retried", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a write called with a context marked by WithRetryableWrites fails with a transient error", func() {
+			err := op.retry(WithRetryableWrites(ctx), false, failing(transient))
+
+			Convey("Then it is retried", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the context is cancelled while waiting to retry", func() {
+			c.retryPolicy.backoff = time.Hour
+			cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			err := op.retry(cancelCtx, true, failing(transient))
+
+			Convey("Then the error is returned without waiting for the backoff", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When retries are not configured", func() {
+			c.retryPolicy = retryPolicy{}
+			err := op.retry(ctx, true, failing(transient))
+
+			Convey("Then the operation is not retried", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// SessionFunc is the type signature of a client function that is to be executed within a session, defined by the
+// session context provided as the parameter to the function - sessionCtx
+// All calls to the mongodb library to be executed within the session must be passed the sessionCtx
+type SessionFunc func(sessionCtx context.Context) error
+
+// WithSession executes the given function - fn - within a causally consistent session, defined by the session context
+// - sessionCtx - and ends the session when the function returns. Within the session, a read sees the writes made
+// before it in the session (read your writes), even if it is made from a secondary, as the session uses majority read
+// and write concerns
+// The session is not a transaction: each operation is applied independently; use RunTransaction for atomic writes
+func (ms *MongoConnection) WithSession(ctx context.Context, fn SessionFunc) error {
+	opts := options.Session().
+		SetCausalConsistency(true).
+		SetDefaultReadConcern(readconcern.Majority()).
+		SetDefaultWriteConcern(writeconcern.Majority())
+
+	session, endSession, err := ms.startSession(ctx, "session", opts)
+	if err != nil {
+		return err
+	}
+	defer endSession()
+
+	return fn(mongo.NewSessionContext(ctx, session))
+}
+
+// startSession starts a session of the given kind (a session or a transaction), returning a function to end it, which
+// must be called when the session is no longer needed so that the server can release it
+// If the session is still active after the session warning threshold, a warning is logged, and another when it ends
+func (ms *MongoConnection) startSession(ctx context.Context, kind string, opts *options.SessionOptions) (mongo.Session, func(), error) {
+	session, err := ms.client.StartSession(opts)
+	if err != nil {
+		return nil, nil, wrapMongoError(err)
+	}
+
+	start := time.Now()
+	ms.activeSessions.Add(1)
+
+	var warning *time.Timer
+	if ms.sessionWarningThreshold > 0 {
+		warning = time.AfterFunc(ms.sessionWarningThreshold, func() {
+			log.Warn(ctx, "mongodb session still active", log.Data{
+				"kind":            kind,
+				"duration":        time.Since(start).String(),
+				"active_sessions": ms.activeSessions.Load(),
+			})
+		})
+	}
+
+	endSession := func() {
+		// end the session even if the context is done, so that the server session is returned to the pool
+		session.EndSession(context.WithoutCancel(ctx))
+
+		active := ms.activeSessions.Add(-1)
+		if warning != nil && !warning.Stop() {
+			log.Warn(ctx, "long-running mongodb session ended", log.Data{
+				"kind":            kind,
+				"duration":        time.Since(start).String(),
+				"active_sessions": active,
+			})
+		}
+	}
+
+	return session, endSession, nil
+}
+
+// ActiveSessions returns the number of sessions started by RunTransaction or WithSession that have not yet ended
+func (ms *MongoConnection) ActiveSessions() int64 {
+	return ms.activeSessions.Load()
+}
@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithSession(t *testing.T) {
+	ctx := context.Background()
+
+	var buf syncBuffer
+	log.SetDestination(&buf, nil)
+	defer log.SetDestination(os.Stdout, os.Stderr)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	Convey("Given a connection with a session warning threshold", t, func() {
+		buf.Reset()
+		conn := NewMongoConnection(client, "test-db")
+		conn.sessionWarningThreshold = 10 * time.Millisecond
+
+		Convey("When WithSession is called", func() {
+			var (
+				session mongo.Session
+				active  int64
+			)
+			err := conn.WithSession(ctx, func(sessionCtx context.Context) error {
+				session = mongo.SessionFromContext(sessionCtx)
+				active = conn.ActiveSessions()
+				return nil
+			})
+
+			Convey("Then the function is called with a causally consistent session, which is ended when it returns", func() {
+				So(err, ShouldBeNil)
+				So(session, ShouldNotBeNil)
+				So(session.(mongo.XSession).ClientSession().Consistent, ShouldBeTrue)
+				So(session.(mongo.XSession).ClientSession().Terminated, ShouldBeTrue)
+				So(active, ShouldEqual, 1)
+				So(conn.ActiveSessions(), ShouldEqual, 0)
+			})
+
+			Convey("Then nothing is logged for a session that ends within the threshold", func() {
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When WithSession is called with a function that holds the session beyond the threshold", func() {
+			var warned bool
+			err := conn.WithSession(ctx, func(context.Context) error {
+				deadline := time.Now().Add(time.Second)
+				for !warned && time.Now().Before(deadline) {
+					time.Sleep(5 * time.Millisecond)
+					warned = strings.Contains(buf.String(), `"event":"mongodb session still active"`)
+				}
+				return nil
+			})
+
+			Convey("Then a warning is logged while the session is active, and another when it ends", func() {
+				So(err, ShouldBeNil)
+				So(warned, ShouldBeTrue)
+				So(buf.String(), ShouldContainSubstring, `"event":"long-running mongodb session ended"`)
+				So(buf.String(), ShouldContainSubstring, `"kind":"session"`)
+				So(buf.String(), ShouldContainSubstring, `"severity":2`)
+				So(buf.String(), ShouldNotContainSubstring, `"severity":3`)
+			})
+		})
+
+		Convey("When the function given to WithSession fails", func() {
+			cause := errors.New("failed")
+			err := conn.WithSession(ctx, func(context.Context) error { return cause })
+
+			Convey("Then its error is returned, and the session is ended", func() {
+				So(err, ShouldEqual, cause)
+				So(conn.ActiveSessions(), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When RunTransaction is called with a function that fails", func() {
+			var session mongo.Session
+			_, err := conn.RunTransaction(ctx, false, func(transactionCtx context.Context) (interface{}, error) {
+				session = mongo.SessionFromContext(transactionCtx)
+				time.Sleep(50 * time.Millisecond)
+				return nil, errors.New("failed")
+			})
+
+			Convey("Then the transaction's session is ended", func() {
+				So(err, ShouldNotBeNil)
+				So(session.(mongo.XSession).ClientSession().Terminated, ShouldBeTrue)
+				So(conn.ActiveSessions(), ShouldEqual, 0)
+				So(buf.String(), ShouldContainSubstring, `"kind":"transaction"`)
+			})
+		})
+	})
+
+	Convey("Given a connection without a session warning threshold", t, func() {
+		buf.Reset()
+		conn := NewMongoConnection(client, "test-db")
+
+		Convey("When WithSession is called with a function that holds the session", func() {
+			err := conn.WithSession(ctx, func(context.Context) error {
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			})
+
+			Convey("Then nothing is logged", func() {
+				So(err, ShouldBeNil)
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+// syncBuffer is a bytes.Buffer that can be written by the logger while being read by a test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
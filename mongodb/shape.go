@@ -0,0 +1,183 @@
+package mongodb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// filterShape returns the shape of a filter, sort order or pipeline, with its structure (field names and operators)
+// kept and every value replaced by a placeholder for its type, e.g. `{"state": ?string, "count": {"$gt": ?number}}`,
+// so that it can be logged or traced without exposing any data. The keys of a map are given in sorted order, so that
+// equivalent filters have the same shape
+// Repeated element shapes in an array are given once, so the shape does not depend on the number of values
+func filterShape(filter interface{}) string {
+	if filter == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	writeShape(&b, filter)
+
+	return b.String()
+}
+
+// rawShape returns the shape of a bson value
+func rawShape(v bson.RawValue) string {
+	var b strings.Builder
+	writeRawShape(&b, v)
+
+	return b.String()
+}
+
+// joinShapes returns the shape of an array whose elements have the given shapes
+func joinShapes(shapes []string) string {
+	var b strings.Builder
+	writeShapes(&b, shapes)
+
+	return b.String()
+}
+
+func writeShape(b *strings.Builder, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		b.WriteString(placeholder(bson.TypeNull))
+	case bson.D:
+		b.WriteString("{")
+		for i, e := range val {
+			writeKey(b, i, e.Key)
+			writeShape(b, e.Value)
+		}
+		b.WriteString("}")
+	case bson.M:
+		writeMapShape(b, val)
+	case map[string]interface{}:
+		writeMapShape(b, val)
+	case bson.A:
+		writeArrayShape(b, []interface{}(val))
+	case []interface{}:
+		writeArrayShape(b, val)
+	case bson.Raw:
+		writeRawShape(b, bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: val})
+	default:
+		t, data, err := bson.MarshalValue(v)
+		if err != nil {
+			b.WriteString("?unknown")
+			return
+		}
+		writeRawShape(b, bson.RawValue{Type: t, Value: data})
+	}
+}
+
+func writeMapShape(b *strings.Builder, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("{")
+	for i, k := range keys {
+		writeKey(b, i, k)
+		writeShape(b, m[k])
+	}
+	b.WriteString("}")
+}
+
+func writeArrayShape(b *strings.Builder, a []interface{}) {
+	shapes := make([]string, 0, len(a))
+	for _, v := range a {
+		var sb strings.Builder
+		writeShape(&sb, v)
+		shapes = append(shapes, sb.String())
+	}
+	writeShapes(b, shapes)
+}
+
+func writeRawShape(b *strings.Builder, v bson.RawValue) {
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			b.WriteString("?unknown")
+			return
+		}
+		b.WriteString("{")
+		for i, e := range elements {
+			writeKey(b, i, e.Key())
+			writeRawShape(b, e.Value())
+		}
+		b.WriteString("}")
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil {
+			b.WriteString("?unknown")
+			return
+		}
+		shapes := make([]string, 0, len(values))
+		for _, value := range values {
+			var sb strings.Builder
+			writeRawShape(&sb, value)
+			shapes = append(shapes, sb.String())
+		}
+		writeShapes(b, shapes)
+	default:
+		b.WriteString(placeholder(v.Type))
+	}
+}
+
+// writeShapes writes the shapes of the elements of an array, giving each distinct shape once, in order
+func writeShapes(b *strings.Builder, shapes []string) {
+	seen := make(map[string]bool, len(shapes))
+
+	b.WriteString("[")
+	for _, s := range shapes {
+		if seen[s] {
+			continue
+		}
+		if len(seen) > 0 {
+			b.WriteString(", ")
+		}
+		seen[s] = true
+		b.WriteString(s)
+	}
+	b.WriteString("]")
+}
+
+func writeKey(b *strings.Builder, i int, key string) {
+	if i > 0 {
+		b.WriteString(", ")
+	}
+	b.WriteString(strconv.Quote(key))
+	b.WriteString(": ")
+}
+
+// placeholder returns the placeholder for a value of the given bson type, following the representation of values in
+// MongoDB query shapes
+func placeholder(t bsontype.Type) string {
+	switch t {
+	case bson.TypeString:
+		return "?string"
+	case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128:
+		return "?number"
+	case bson.TypeBoolean:
+		return "?bool"
+	case bson.TypeDateTime:
+		return "?date"
+	case bson.TypeTimestamp:
+		return "?timestamp"
+	case bson.TypeObjectID:
+		return "?objectId"
+	case bson.TypeNull, bson.TypeUndefined:
+		return "?null"
+	case bson.TypeRegex:
+		return "?regex"
+	case bson.TypeBinary:
+		return "?binData"
+	default:
+		return "?" + t.String()
+	}
+}
@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterShape(t *testing.T) {
+	Convey("Given filters, sort orders and pipelines", t, func() {
+		Convey("Then a nil filter has no shape", func() {
+			So(filterShape(nil), ShouldEqual, "")
+		})
+
+		Convey("Then the values of a filter are replaced by placeholders for their types", func() {
+			filter := bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "state", Value: "published"},
+				{Key: "count", Value: bson.M{"$gt": 5, "$lte": 10.5}},
+				{Key: "updated", Value: time.Now()},
+				{Key: "deleted", Value: nil},
+			}
+			So(filterShape(filter), ShouldEqual,
+				`{"_id": ?objectId, "state": ?string, "count": {"$gt": ?number, "$lte": ?number}, "updated": ?date, "deleted": ?null}`)
+		})
+
+		Convey("Then the keys of a map are given in sorted order", func() {
+			So(filterShape(bson.M{"b": true, "a": int64(1)}), ShouldEqual, `{"a": ?number, "b": ?bool}`)
+		})
+
+		Convey("Then the shape of an array does not depend on the number of values", func() {
+			So(filterShape(bson.M{"id": bson.M{"$in": bson.A{"a", "b", "c"}}}), ShouldEqual, `{"id": {"$in": [?string]}}`)
+			So(filterShape(bson.M{"$or": []interface{}{bson.M{"a": 1}, bson.M{"b": "x"}}}), ShouldEqual, `{"$or": [{"a": ?number}, {"b": ?string}]}`)
+		})
+
+		Convey("Then the fields of a struct are given in order", func() {
+			filter := struct {
+				State string `bson:"state"`
+				Count int    `bson:"count"`
+			}{"published", 1}
+			So(filterShape(filter), ShouldEqual, `{"state": ?string, "count": ?number}`)
+		})
+
+		Convey("Then the stages of a pipeline are given in order", func() {
+			pipeline := bson.A{
+				bson.M{"$match": bson.M{"state": "published"}},
+				bson.M{"$sort": bson.D{{Key: "b", Value: -1}, {Key: "a", Value: 1}}},
+			}
+			So(filterShape(pipeline), ShouldEqual, `[{"$match": {"state": ?string}}, {"$sort": {"b": ?number, "a": ?number}}]`)
+		})
+	})
+}
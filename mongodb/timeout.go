@@ -0,0 +1,30 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+)
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout returns a copy of the given context that overrides the configured QueryTimeout for any Collection
+// (or Must) operation called with it. A timeout <= 0 disables the query timeout for those operations, leaving them
+// bounded only by the context's own deadline (if any)
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// queryContext derives the context for a single collection operation, with a deadline given by the query timeout.
+// If the incoming context already has a tighter deadline, that deadline is kept
+func (c *Collection) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.queryTimeout
+	if t, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		timeout = t
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
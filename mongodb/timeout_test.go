@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryContext(t *testing.T) {
+	Convey("Given a collection with a configured query timeout", t, func() {
+		c := &Collection{queryTimeout: time.Minute}
+
+		Convey("When an operation context without a deadline is derived", func() {
+			ctx, cancel := c.queryContext(context.Background())
+			defer cancel()
+
+			Convey("Then the context has a deadline given by the query timeout", func() {
+				deadline, ok := ctx.Deadline()
+				So(ok, ShouldBeTrue)
+				So(time.Until(deadline), ShouldBeBetweenOrEqual, 59*time.Second, time.Minute)
+			})
+		})
+
+		Convey("When an operation context is derived from a context with a tighter deadline", func() {
+			parent, parentCancel := context.WithTimeout(context.Background(), time.Second)
+			defer parentCancel()
+			parentDeadline, _ := parent.Deadline()
+
+			ctx, cancel := c.queryContext(parent)
+			defer cancel()
+
+			Convey("Then the tighter deadline is kept", func() {
+				deadline, ok := ctx.Deadline()
+				So(ok, ShouldBeTrue)
+				So(deadline, ShouldEqual, parentDeadline)
+			})
+		})
+
+		Convey("When an operation context is derived from a context overriding the query timeout", func() {
+			ctx, cancel := c.queryContext(WithQueryTimeout(context.Background(), time.Second))
+			defer cancel()
+
+			Convey("Then the context has a deadline given by the override", func() {
+				deadline, ok := ctx.Deadline()
+				So(ok, ShouldBeTrue)
+				So(time.Until(deadline), ShouldBeLessThanOrEqualTo, time.Second)
+			})
+		})
+
+		Convey("When an operation context is derived from a context disabling the query timeout", func() {
+			ctx, cancel := c.queryContext(WithQueryTimeout(context.Background(), 0))
+			defer cancel()
+
+			Convey("Then the context has no deadline", func() {
+				_, ok := ctx.Deadline()
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a collection without a configured query timeout", t, func() {
+		c := &Collection{}
+
+		Convey("When an operation context is derived", func() {
+			ctx, cancel := c.queryContext(context.Background())
+			defer cancel()
+
+			Convey("Then the context has no deadline", func() {
+				_, ok := ctx.Deadline()
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
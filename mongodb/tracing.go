@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys of the recorded spans, following the OpenTelemetry database semantic conventions
+const (
+	dbSystemKey        = attribute.Key("db.system")
+	dbNameKey          = attribute.Key("db.name")
+	dbSpanOperationKey = attribute.Key("db.operation")
+	dbStatementKey     = attribute.Key("db.statement")
+)
+
+var dbSystemMongoDB = dbSystemKey.String("mongodb")
+
+func getTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer("dp-mongodb")
+}
+
+// startSpan starts a span for the named operation on the collection, as a child of any span in the given context,
+// returning the context carrying the new span so that the work done by the operation is nested under it
+func (c *Collection) startSpan(ctx context.Context, spanName, operation string) (context.Context, trace.Span) {
+	return getTracer().Start(ctx, spanName, trace.WithAttributes(
+		dbSystemMongoDB,
+		dbNameKey.String(c.collection.Database().Name()),
+		dbCollectionKey.String(c.collection.Name()),
+		dbSpanOperationKey.String(operation),
+	))
+}
+
+// endSpan records the error, if any, on the span and ends it. A document not being found is an expected outcome of an
+// operation, and is not recorded as an error
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, ErrNoDocumentFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOperationSpans(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	Convey("Given a tracer provider recording spans", t, func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		parentCtx, parent := getTracer().Start(ctx, "parent")
+		c := NewCollection(client.Database("test-db").Collection("test-collection"))
+
+		Convey("When an operation fails", func() {
+			opCtx, op := c.startOperation(parentCtx, "Find")
+			op.filter(bson.M{"state": "published"})
+			var opErr error = Error{inner: mongo.CommandError{Code: 2, Message: "bad query"}}
+			op.end(&opErr)
+
+			Convey("Then a child span with the database attributes is recorded, with the error", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Name(), ShouldEqual, "collection.Find")
+				So(spans[0].Parent().SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+				So(spanAttribute(spans[0], dbSystemKey), ShouldEqual, "mongodb")
+				So(spanAttribute(spans[0], dbNameKey), ShouldEqual, "test-db")
+				So(spanAttribute(spans[0], dbCollectionKey), ShouldEqual, "test-collection")
+				So(spanAttribute(spans[0], dbSpanOperationKey), ShouldEqual, "Find")
+				So(spans[0].Status().Code, ShouldEqual, codes.Error)
+				So(spans[0].Events(), ShouldHaveLength, 1)
+
+				Convey("And the context returned carries the operation span", func() {
+					So(spans[0].SpanContext().SpanID(), ShouldEqual, trace.SpanFromContext(opCtx).SpanContext().SpanID())
+				})
+
+				Convey("And the filter shape is not included by default", func() {
+					So(spanAttribute(spans[0], dbStatementKey), ShouldEqual, "")
+				})
+			})
+		})
+
+		Convey("When an operation does not find a document, with tracing of filter shapes enabled", func() {
+			c.traceFilterShapes = true
+			_, op := c.startOperation(parentCtx, "FindOne")
+			op.filter(bson.M{"state": "published"})
+			notFound := ErrNoDocumentFound
+			op.end(&notFound)
+
+			Convey("Then the span includes the filter shape, and is not recorded as an error", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spanAttribute(spans[0], dbStatementKey), ShouldEqual, `{"state": ?string}`)
+				So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+			})
+		})
+
+		Convey("When the command monitor observes commands sent by an operation", func() {
+			monitor := NewCommandMonitor()
+			find, err := bson.Marshal(bson.D{{Key: "find", Value: "test-collection"}})
+			So(err, ShouldBeNil)
+			monitor.Started(parentCtx, &event.CommandStartedEvent{Command: find, DatabaseName: "test-db", CommandName: "find", RequestID: 1, ConnectionID: "conn"})
+			monitor.Started(parentCtx, &event.CommandStartedEvent{Command: find, DatabaseName: "test-db", CommandName: "find", RequestID: 2, ConnectionID: "conn"})
+			monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 1, ConnectionID: "conn"}})
+			monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 2, ConnectionID: "conn"}, Failure: "interrupted"})
+
+			Convey("Then a client span is recorded for each command, as a child of the operation span", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 2)
+				for _, span := range spans {
+					So(span.Name(), ShouldEqual, "mongodb.find")
+					So(span.Parent().SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+					So(spanAttribute(span, dbNameKey), ShouldEqual, "test-db")
+					So(spanAttribute(span, dbCollectionKey), ShouldEqual, "test-collection")
+					So(spanAttribute(span, dbSpanOperationKey), ShouldEqual, "find")
+				}
+				So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+				So(spans[1].Status().Code, ShouldEqual, codes.Error)
+				So(spans[1].Status().Description, ShouldEqual, "interrupted")
+			})
+		})
+	})
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsString()
+		}
+	}
+
+	return ""
+}
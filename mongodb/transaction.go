@@ -2,7 +2,10 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/ONSdigital/log.go/v2/log"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
@@ -10,46 +13,280 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
+const (
+	// defaultTransactionRetryTime is the time after which a transaction is no longer retried, if neither a maximum
+	// number of retries nor a timeout is given, as for the driver's Session.WithTransaction
+	defaultTransactionRetryTime = 120 * time.Second
+
+	transientTransactionErrorLabel      = "TransientTransactionError"
+	unknownTransactionCommitResultLabel = "UnknownTransactionCommitResult"
+)
+
 // TransactionFunc is the type signature of a client function that is to be executed within a transaction,
 // defined by the transaction context provided as the parameter to the function - transactionCtx
 // All calls to the mongodb library to be executed within the transaction must be passed the transactionCtx
 // Returning an error from the function indicates the transaction is to be aborted
 type TransactionFunc func(transactionCtx context.Context) (interface{}, error)
 
+// ErrNestedTransaction is returned by RunTransaction, given the PropagationNever option, when called with the context
+// of a transaction
+var ErrNestedTransaction = errors.New("RunTransaction called with the context of a transaction that is already in progress")
+
+// Propagation is the behaviour of RunTransaction when it is called with the context of a transaction in progress, e.g.
+// by a helper called from a TransactionFunc
+type Propagation int
+
+const (
+	// PropagationRequired joins the transaction in progress: the function is executed within that transaction, which
+	// commits or aborts as a whole. This is the default
+	PropagationRequired Propagation = iota
+	// PropagationNever returns ErrNestedTransaction, without executing the function
+	PropagationNever
+)
+
+type TransactionOption func(*transactionOptions)
+
+var (
+	TransactionReadConcern = func(rc *readconcern.ReadConcern) TransactionOption {
+		return func(t *transactionOptions) { t.readConcern = rc }
+	}
+	TransactionWriteConcern = func(wc *writeconcern.WriteConcern) TransactionOption {
+		return func(t *transactionOptions) { t.writeConcern = wc }
+	}
+	TransactionReadPreference = func(rp *readpref.ReadPref) TransactionOption {
+		return func(t *transactionOptions) { t.readPreference = rp }
+	}
+	MaxCommitTime = func(d time.Duration) TransactionOption { return func(t *transactionOptions) { t.maxCommitTime = d } }
+
+	// TransactionTimeout bounds the whole transaction, including all its retries
+	TransactionTimeout = func(d time.Duration) TransactionOption { return func(t *transactionOptions) { t.timeout = d } }
+
+	// MaxTransactionRetries bounds the number of times a transaction, or its commit, is retried
+	MaxTransactionRetries = func(n int) TransactionOption { return func(t *transactionOptions) { t.maxRetries = &n } }
+
+	// TransactionRetryBackoff sets the backoff before the first retry of a transaction, which doubles for each further
+	// retry up to the maximum backoff. By default, a transaction is retried immediately
+	TransactionRetryBackoff = func(backoff, maxBackoff time.Duration) TransactionOption {
+		return func(t *transactionOptions) { t.backoff, t.maxBackoff = backoff, maxBackoff }
+	}
+
+	// RetryOnErrorLabels sets the labels of the errors after which a transaction is retried, in place of the default
+	// TransientTransactionError and UnknownTransactionCommitResult. An error labelled UnknownTransactionCommitResult
+	// causes the commit to be retried, and an error with any other of the labels the whole transaction
+	RetryOnErrorLabels = func(labels ...string) TransactionOption {
+		return func(t *transactionOptions) { t.retryLabels = labels }
+	}
+
+	// TransactionPropagation sets the behaviour when called with the context of a transaction in progress
+	TransactionPropagation = func(p Propagation) TransactionOption { return func(t *transactionOptions) { t.propagation = p } }
+)
+
+type transactionOptions struct {
+	readConcern    *readconcern.ReadConcern
+	writeConcern   *writeconcern.WriteConcern
+	readPreference *readpref.ReadPref
+	maxCommitTime  time.Duration
+	timeout        time.Duration
+	maxRetries     *int
+	backoff        time.Duration
+	maxBackoff     time.Duration
+	retryLabels    []string
+	propagation    Propagation
+}
+
+func newTransactionOptions(opts ...TransactionOption) *transactionOptions {
+	t := &transactionOptions{
+		readConcern:    readconcern.Snapshot(),
+		writeConcern:   writeconcern.New(writeconcern.WMajority()),
+		readPreference: readpref.Primary(),
+		retryLabels:    []string{transientTransactionErrorLabel, unknownTransactionCommitResultLabel},
+	}
+	for _, o := range opts {
+		o(t)
+	}
+
+	return t
+}
+
+func (to transactionOptions) asDriverTransactionOption() *options.TransactionOptions {
+	o := options.Transaction().
+		SetReadConcern(to.readConcern).
+		SetWriteConcern(to.writeConcern).
+		SetReadPreference(to.readPreference)
+	if to.maxCommitTime > 0 {
+		o.SetMaxCommitTime(&to.maxCommitTime)
+	}
+
+	return o
+}
+
+// shouldRetry returns true if the error has one of the retry labels. For a commit error, only the
+// UnknownTransactionCommitResult label causes the commit to be retried; the other labels cause the whole transaction
+// to be retried
+func (to transactionOptions) shouldRetry(err error, commit bool) bool {
+	var labeled mongo.LabeledError
+	if !errors.As(err, &labeled) {
+		return false
+	}
+
+	for _, l := range to.retryLabels {
+		if (l == unknownTransactionCommitResultLabel) == commit && labeled.HasErrorLabel(l) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RunTransaction will execute the given function - fn - within a transaction, defined by the transaction context - transactionCtx
 // If withRetries is true, the transaction will retry on a transient transaction error - this can be due to a network
 // error, but may also occur if the state of an object being updated in the transaction has been changed since the transaction
 // started. This latter behaviour may or may be suitable depending on the circumstances, and so is optional
+// If the context is the transaction context of a transaction in progress, by default the function joins that transaction, and is executed
+// with the given context without starting a new transaction; the other options are then ignored, and an error returned
+// by the function must be returned by the enclosing TransactionFunc to abort the transaction
+// By default, the transaction uses snapshot read concern, primary read preference and majority write concern, and is
+// retried until it succeeds or 120 seconds have passed; the options override these
 // The return values of the function are the return values provided by the TransactionFunc fn, except in the case where
 // runtime errors occur outside the TransactionFunc fn, when committing or aborting the transaction
-func (ms *MongoConnection) RunTransaction(ctx context.Context, withRetries bool, fn TransactionFunc) (interface{}, error) {
-	opts := options.Session().
+func (ms *MongoConnection) RunTransaction(ctx context.Context, withRetries bool, fn TransactionFunc, opts ...TransactionOption) (interface{}, error) {
+	to := newTransactionOptions(opts...)
+
+	if InTransaction(ctx) {
+		if to.propagation == PropagationNever {
+			return nil, ErrNestedTransaction
+		}
+		return fn(ctx)
+	}
+
+	if !withRetries {
+		noRetries := 0
+		to.maxRetries = &noRetries
+	}
+
+	sessionOpts := options.Session().
 		SetCausalConsistency(false).
 		SetDefaultReadPreference(readpref.Primary()).
 		SetDefaultReadConcern(readconcern.Snapshot()).
 		SetDefaultWriteConcern(writeconcern.New(writeconcern.WMajority()))
 
-	session, err := ms.d().Client().StartSession(opts)
+	session, endSession, err := ms.startSession(ctx, "transaction", sessionOpts)
 	if err != nil {
-		return nil, wrapMongoError(err)
+		return nil, err
 	}
+	defer endSession()
 
-	if withRetries {
-		return session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) { return fn(sessionCtx) })
+	transactionCtx := ctx
+	if to.timeout > 0 {
+		var cancel context.CancelFunc
+		transactionCtx, cancel = context.WithTimeout(ctx, to.timeout)
+		defer cancel()
 	}
 
-	if err := session.StartTransaction(); err != nil {
-		return nil, wrapMongoError(err)
+	return runTransaction(ctx, mongo.NewSessionContext(transactionCtx, session), session, to, fn)
+}
+
+// InTransaction returns true if the context is the transaction context given to a TransactionFunc by RunTransaction
+func InTransaction(ctx context.Context) bool {
+	_, ok := transactionHooksFrom(ctx)
+	return ok
+}
+
+// transactionSession is the part of a mongo.Session used to run a transaction
+type transactionSession interface {
+	StartTransaction(...*options.TransactionOptions) error
+	CommitTransaction(context.Context) error
+	AbortTransaction(context.Context) error
+}
+
+// runTransaction executes fn in a transaction of the session, retrying the transaction, or its commit, after an error
+// with one of the retry labels, up to the maximum number of retries or until the retry time has passed
+// The hooks registered by the last attempt of the transaction are then called with ctx
+func runTransaction(ctx, sessionCtx context.Context, session transactionSession, to *transactionOptions, fn TransactionFunc) (interface{}, error) {
+	policy := retryPolicy{backoff: to.backoff, maxBackoff: to.maxBackoff}
+	deadline := time.Now().Add(defaultTransactionRetryTime)
+	canRetry := func(retries int) bool {
+		if sessionCtx.Err() != nil {
+			return false
+		}
+		if to.maxRetries != nil {
+			return retries < *to.maxRetries
+		}
+		return to.timeout > 0 || time.Now().Before(deadline)
 	}
 
-	sessionContext := mongo.NewSessionContext(ctx, session)
-	res, err := fn(sessionContext)
-	if err != nil {
-		if me := sessionContext.AbortTransaction(sessionContext); me != nil {
-			return res, wrapMongoError(me)
+	retries := 0
+	retry := func(err error) bool {
+		if !canRetry(retries) {
+			return false
 		}
+		retries++
+		return transactionRetried(sessionCtx, retries, policy, err)
+	}
+
+	for {
+		if err := session.StartTransaction(to.asDriverTransactionOption()); err != nil {
+			return nil, wrapMongoError(err)
+		}
+
+		attemptCtx, hooks := withTransactionHooks(sessionCtx)
+		res, err := fn(attemptCtx)
+		if err != nil {
+			// abort even if the context is done, so that the server does not keep the transaction open
+			if me := session.AbortTransaction(context.WithoutCancel(sessionCtx)); me != nil {
+				err = wrapMongoError(me)
+				hooks.aborted(ctx, err)
+				return res, err
+			}
+			if to.shouldRetry(err, false) && retry(err) {
+				continue
+			}
+			hooks.aborted(ctx, err)
+			return res, err
+		}
+
+		err = session.CommitTransaction(sessionCtx)
+		for err != nil && to.shouldRetry(err, true) && !mongo.IsTimeout(err) && retry(err) {
+			err = session.CommitTransaction(sessionCtx)
+		}
+		if err == nil {
+			hooks.committed(ctx)
+			return res, nil
+		}
+		if to.shouldRetry(err, false) && retry(err) {
+			continue
+		}
+
+		err = wrapMongoError(err)
+		hooks.aborted(ctx, err)
 		return res, err
 	}
+}
+
+// transactionRetried logs a retry of a transaction after the given error and waits for the backoff, if any. It returns
+// false if the context was done while waiting
+func transactionRetried(ctx context.Context, retry int, policy retryPolicy, err error) bool {
+	var delay time.Duration
+	if policy.backoff > 0 {
+		delay = policy.delay(retry - 1)
+	}
 
-	return res, wrapMongoError(sessionContext.CommitTransaction(sessionContext))
+	log.Warn(ctx, "retrying mongodb transaction", log.Data{
+		"retry":   retry,
+		"backoff": delay.String(),
+		"error":   err.Error(),
+	})
+
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoTransaction is returned when registering a transaction hook with a context that is not a transaction context
+var ErrNoTransaction = errors.New("the context is not a transaction context given by RunTransaction")
+
+// transactionHooks are the functions registered to be called when a transaction commits or aborts
+type transactionHooks struct {
+	mu       sync.Mutex
+	onCommit []func(ctx context.Context)
+	onAbort  []func(ctx context.Context, err error)
+}
+
+type transactionHooksKey struct{}
+
+// withTransactionHooks returns a copy of the context carrying the hooks of a new transaction attempt, which also marks
+// the context as a transaction context for InTransaction
+func withTransactionHooks(ctx context.Context) (context.Context, *transactionHooks) {
+	hooks := &transactionHooks{}
+	return context.WithValue(ctx, transactionHooksKey{}, hooks), hooks
+}
+
+func transactionHooksFrom(ctx context.Context) (*transactionHooks, bool) {
+	hooks, ok := ctx.Value(transactionHooksKey{}).(*transactionHooks)
+	return hooks, ok
+}
+
+// OnCommit registers a function to be called after the transaction of the given transaction context commits, e.g. to
+// publish an event or invalidate a cache only if the transaction's writes are applied
+// The function is called once, with the context given to RunTransaction, even if the transaction is retried; the
+// functions registered by an attempt of the transaction that is retried are discarded, since the TransactionFunc
+// registers them again
+func OnCommit(transactionCtx context.Context, fn func(ctx context.Context)) error {
+	hooks, ok := transactionHooksFrom(transactionCtx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.onCommit = append(hooks.onCommit, fn)
+
+	return nil
+}
+
+// OnAbort registers a function to be called, with the error returned by RunTransaction, after the transaction of the
+// given transaction context is aborted or fails to commit (including when the result of the commit is unknown), and
+// is not retried
+// The function is called once, with the context given to RunTransaction; the functions registered by an attempt of
+// the transaction that is retried are discarded, since the TransactionFunc registers them again
+func OnAbort(transactionCtx context.Context, fn func(ctx context.Context, err error)) error {
+	hooks, ok := transactionHooksFrom(transactionCtx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.onAbort = append(hooks.onAbort, fn)
+
+	return nil
+}
+
+// committed calls the commit hooks, in the order they were registered
+func (h *transactionHooks) committed(ctx context.Context) {
+	h.mu.Lock()
+	onCommit := h.onCommit
+	h.mu.Unlock()
+
+	for _, fn := range onCommit {
+		fn(ctx)
+	}
+}
+
+// aborted calls the abort hooks, in the order they were registered
+func (h *transactionHooks) aborted(ctx context.Context, err error) {
+	h.mu.Lock()
+	onAbort := h.onAbort
+	h.mu.Unlock()
+
+	for _, fn := range onAbort {
+		fn(ctx, err)
+	}
+}
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type hookCtxKey struct{}
+
+func TestTransactionHooks(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hookCtxKey{}, "caller")
+	sessionCtx := context.WithValue(ctx, hookCtxKey{}, "session")
+
+	transient := mongo.CommandError{Code: 112, Name: "WriteConflict", Labels: []string{transientTransactionErrorLabel}}
+
+	Convey("Given a transaction function that registers commit and abort hooks", t, func() {
+		session := &fakeSession{}
+
+		var (
+			committed []string
+			aborted   []error
+		)
+		calls := 0
+		fn := func(errs ...error) TransactionFunc {
+			return func(transactionCtx context.Context) (interface{}, error) {
+				calls++
+				So(OnCommit(transactionCtx, func(ctx context.Context) {
+					committed = append(committed, ctx.Value(hookCtxKey{}).(string))
+				}), ShouldBeNil)
+				So(OnAbort(transactionCtx, func(ctx context.Context, err error) { aborted = append(aborted, err) }), ShouldBeNil)
+				if calls <= len(errs) {
+					return nil, errs[calls-1]
+				}
+				return nil, nil
+			}
+		}
+
+		Convey("When the transaction commits", func() {
+			_, err := runTransaction(ctx, sessionCtx, session, newTransactionOptions(), fn())
+
+			Convey("Then the commit hooks are called once, with the caller's context, and the abort hooks are not", func() {
+				So(err, ShouldBeNil)
+				So(committed, ShouldResemble, []string{"caller"})
+				So(aborted, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the transaction is retried before it commits", func() {
+			_, err := runTransaction(ctx, sessionCtx, session, newTransactionOptions(), fn(transient, transient))
+
+			Convey("Then only the hooks registered by the attempt that commits are called", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 3)
+				So(committed, ShouldHaveLength, 1)
+				So(aborted, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the commit result is unknown and the commit is retried", func() {
+			session.commitErrs = []error{mongo.CommandError{Code: 91, Labels: []string{unknownTransactionCommitResultLabel}}}
+			_, err := runTransaction(ctx, sessionCtx, session, newTransactionOptions(), fn())
+
+			Convey("Then the commit hooks are called once", func() {
+				So(err, ShouldBeNil)
+				So(session.committed, ShouldEqual, 2)
+				So(committed, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the transaction function fails", func() {
+			cause := errors.New("bad object state")
+			_, err := runTransaction(ctx, sessionCtx, session, newTransactionOptions(), fn(cause))
+
+			Convey("Then the abort hooks are called once with the error, and the commit hooks are not", func() {
+				So(err, ShouldEqual, cause)
+				So(aborted, ShouldResemble, []error{cause})
+				So(committed, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the commit fails", func() {
+			commitErr := mongo.CommandError{Code: 50, Name: "MaxTimeMSExpired"}
+			session.commitErrs = []error{commitErr}
+			_, err := runTransaction(ctx, sessionCtx, session, newTransactionOptions(), fn())
+
+			Convey("Then the abort hooks are called with the error", func() {
+				So(err, ShouldResemble, Error{inner: commitErr})
+				So(aborted, ShouldResemble, []error{err})
+				So(committed, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a context that is not a transaction context", t, func() {
+		Convey("Then hooks cannot be registered", func() {
+			So(OnCommit(ctx, func(context.Context) {}), ShouldEqual, ErrNoTransaction)
+			So(OnAbort(ctx, func(context.Context, error) {}), ShouldEqual, ErrNoTransaction)
+		})
+	})
+}
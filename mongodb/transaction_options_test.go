@@ -0,0 +1,253 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeSession records the transactions started, committed and aborted, and returns the given commit errors in turn
+type fakeSession struct {
+	started, committed, aborted int
+	commitErrs                  []error
+	opts                        *options.TransactionOptions
+}
+
+func (s *fakeSession) StartTransaction(opts ...*options.TransactionOptions) error {
+	s.started++
+	s.opts = opts[0]
+	return nil
+}
+
+func (s *fakeSession) CommitTransaction(context.Context) error {
+	s.committed++
+	if s.committed <= len(s.commitErrs) {
+		return s.commitErrs[s.committed-1]
+	}
+	return nil
+}
+
+func (s *fakeSession) AbortTransaction(context.Context) error {
+	s.aborted++
+	return nil
+}
+
+func TestRunTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	log.SetDestination(&buf, nil)
+	defer log.SetDestination(os.Stdout, os.Stderr)
+
+	transient := mongo.CommandError{Code: 112, Name: "WriteConflict", Labels: []string{transientTransactionErrorLabel}}
+	unknownCommit := mongo.CommandError{Code: 91, Labels: []string{unknownTransactionCommitResultLabel}}
+
+	Convey("Given a session", t, func() {
+		buf.Reset()
+		session := &fakeSession{}
+
+		calls := 0
+		failing := func(errs ...error) TransactionFunc {
+			return func(context.Context) (interface{}, error) {
+				calls++
+				if calls <= len(errs) {
+					return nil, errs[calls-1]
+				}
+				return calls, nil
+			}
+		}
+
+		Convey("When a transaction is run with the default options", func() {
+			res, err := runTransaction(ctx, ctx, session, newTransactionOptions(), failing())
+
+			Convey("Then it is committed with snapshot read concern and majority write concern", func() {
+				So(err, ShouldBeNil)
+				So(res, ShouldEqual, 1)
+				So(session.committed, ShouldEqual, 1)
+				So(session.opts.ReadConcern, ShouldResemble, readconcern.Snapshot())
+				So(session.opts.WriteConcern, ShouldResemble, writeconcern.Majority())
+				So(session.opts.MaxCommitTime, ShouldBeNil)
+			})
+		})
+
+		Convey("When a transaction is run with concern and commit time options", func() {
+			_, err := runTransaction(ctx, ctx, session, newTransactionOptions(
+				TransactionReadConcern(readconcern.Majority()),
+				TransactionWriteConcern(writeconcern.W1()),
+				MaxCommitTime(time.Second),
+			), failing())
+
+			Convey("Then the transaction is started with the options", func() {
+				So(err, ShouldBeNil)
+				So(session.opts.ReadConcern, ShouldResemble, readconcern.Majority())
+				So(session.opts.WriteConcern, ShouldResemble, writeconcern.W1())
+				So(*session.opts.MaxCommitTime, ShouldEqual, time.Second)
+			})
+		})
+
+		Convey("When the transaction function fails with a transient transaction error and then succeeds", func() {
+			res, err := runTransaction(ctx, ctx, session, newTransactionOptions(), failing(Error{inner: transient}))
+
+			Convey("Then the transaction is aborted and retried, and the retry is logged", func() {
+				So(err, ShouldBeNil)
+				So(res, ShouldEqual, 2)
+				So(session.aborted, ShouldEqual, 1)
+				So(session.started, ShouldEqual, 2)
+				So(session.committed, ShouldEqual, 1)
+				So(buf.String(), ShouldContainSubstring, `"event":"retrying mongodb transaction"`)
+			})
+		})
+
+		Convey("When the transaction function keeps failing with a transient error, with a bounded number of retries", func() {
+			_, err := runTransaction(ctx, ctx, session, newTransactionOptions(MaxTransactionRetries(2)),
+				failing(transient, transient, transient, transient))
+
+			Convey("Then the transaction is retried up to the maximum, and the last error is returned", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 3)
+				So(session.aborted, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When the transaction function fails with an error that is not labelled for retry", func() {
+			cause := errors.New("bad object state")
+			_, err := runTransaction(ctx, ctx, session, newTransactionOptions(), failing(cause))
+
+			Convey("Then the transaction is aborted and not retried", func() {
+				So(err, ShouldEqual, cause)
+				So(calls, ShouldEqual, 1)
+				So(session.aborted, ShouldEqual, 1)
+				So(session.committed, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the commit fails with an unknown commit result and then succeeds", func() {
+			session.commitErrs = []error{unknownCommit}
+			_, err := runTransaction(ctx, ctx, session, newTransactionOptions(), failing())
+
+			Convey("Then only the commit is retried", func() {
+				So(err, ShouldBeNil)
+				So(calls, ShouldEqual, 1)
+				So(session.committed, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the commit fails with a transient transaction error", func() {
+			session.commitErrs = []error{transient}
+			res, err := runTransaction(ctx, ctx, session, newTransactionOptions(), failing())
+
+			Convey("Then the whole transaction is retried", func() {
+				So(err, ShouldBeNil)
+				So(res, ShouldEqual, 2)
+				So(session.started, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the retry labels are restricted to TransientTransactionError and the commit result is unknown", func() {
+			session.commitErrs = []error{unknownCommit}
+			_, err := runTransaction(ctx, ctx, session, newTransactionOptions(RetryOnErrorLabels(transientTransactionErrorLabel)), failing())
+
+			Convey("Then the commit is not retried, and the error is returned", func() {
+				So(ErrorCode(err), ShouldEqual, 91)
+				So(session.committed, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the transaction is run with a backoff, and the context is done while waiting to retry", func() {
+			timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			_, err := runTransaction(timeoutCtx, timeoutCtx, session, newTransactionOptions(TransactionRetryBackoff(time.Hour, time.Hour)), failing(transient))
+
+			Convey("Then the error is returned without waiting for the backoff", func() {
+				So(err, ShouldEqual, transient)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestRunTransaction_Nested(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	Convey("Given a connection", t, func() {
+		conn := NewMongoConnection(client, "test-db")
+
+		Convey("Then only the context of a transaction in progress is in a transaction", func() {
+			So(InTransaction(ctx), ShouldBeFalse)
+			So(conn.WithSession(ctx, func(sessionCtx context.Context) error {
+				So(InTransaction(sessionCtx), ShouldBeFalse)
+				return nil
+			}), ShouldBeNil)
+			_, err := conn.RunTransaction(ctx, false, func(transactionCtx context.Context) (interface{}, error) {
+				So(InTransaction(transactionCtx), ShouldBeTrue)
+				return nil, nil
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the context of a session in which a transaction was started directly with the driver is not a transaction context", func() {
+			session, err := client.StartSession()
+			So(err, ShouldBeNil)
+			defer session.EndSession(ctx)
+			So(session.StartTransaction(), ShouldBeNil)
+			So(InTransaction(mongo.NewSessionContext(ctx, session)), ShouldBeFalse)
+		})
+
+		Convey("When RunTransaction is called with the context of a transaction in progress", func() {
+			var (
+				outer, inner mongo.Session
+				active       int64
+				committed    bool
+			)
+			res, err := conn.RunTransaction(ctx, true, func(transactionCtx context.Context) (interface{}, error) {
+				outer = mongo.SessionFromContext(transactionCtx)
+				return conn.RunTransaction(transactionCtx, false, func(nestedCtx context.Context) (interface{}, error) {
+					inner = mongo.SessionFromContext(nestedCtx)
+					active = conn.ActiveSessions()
+					return "nested", OnCommit(nestedCtx, func(context.Context) { committed = true })
+				})
+			})
+
+			Convey("Then the function joins the transaction in progress, and its hooks are called when it commits", func() {
+				So(err, ShouldBeNil)
+				So(res, ShouldEqual, "nested")
+				So(inner, ShouldEqual, outer)
+				So(active, ShouldEqual, 1)
+				So(committed, ShouldBeTrue)
+			})
+		})
+
+		Convey("When RunTransaction is called with the context of a transaction in progress and the PropagationNever option", func() {
+			called := false
+			_, err := conn.RunTransaction(ctx, false, func(transactionCtx context.Context) (interface{}, error) {
+				return conn.RunTransaction(transactionCtx, false, func(context.Context) (interface{}, error) {
+					called = true
+					return nil, nil
+				}, TransactionPropagation(PropagationNever))
+			})
+
+			Convey("Then ErrNestedTransaction is returned without calling the function, and the transaction is aborted", func() {
+				So(err, ShouldEqual, ErrNestedTransaction)
+				So(called, ShouldBeFalse)
+				So(conn.ActiveSessions(), ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -140,6 +140,14 @@ func TestTransaction(t *testing.T) {
 				})
 			})
 
+			Convey("when the example transaction is run with retries bounded to none and with an interleaved outside transaction", func() {
+				r, e := conn.RunTransaction(ctx, true, exampleTransactionFunc(conn, true), mongoDriver.MaxTransactionRetries(0))
+				Convey("the transaction is aborted and fails", func() {
+					So(e, ShouldNotBeNil)
+					So(r, ShouldBeNil)
+				})
+			})
+
 			Convey("when the example transaction is run with retries and with an interleaved outside transaction", func() {
 				r, e := conn.RunTransaction(ctx, true, exampleTransactionFunc(conn, true))
 				Convey("the transaction completes successfully, since it is retried and the retry succeeds, but with different results from the above successful completions", func() {
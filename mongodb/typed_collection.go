@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"context"
+)
+
+// TypedCollection is a handle to a MongoDB collection whose documents are all of type T
+// It wraps a Collection, so that the read and insert operations take and return values of type T rather than
+// interface{} values, and decode errors are caught at compile time rather than at runtime
+type TypedCollection[T any] struct {
+	collection Collector
+}
+
+// TypedCursor is a cursor iterating over documents of type T
+type TypedCursor[T any] struct {
+	cursor Cursor
+}
+
+// NewTypedCollection creates a new typed collection wrapping the given collection
+func NewTypedCollection[T any](collection Collector) *TypedCollection[T] {
+	return &TypedCollection[T]{collection}
+}
+
+// Collection returns the underlying untyped collection, giving access to the operations (such as updates and
+// deletes) that do not involve documents of type T
+func (tc *TypedCollection[T]) Collection() Collector {
+	return tc.collection
+}
+
+// Find returns the documents in the collection that satisfy the given filter (restricted by the given options),
+// together with the total number of documents that satisfy the filter
+// If no sort order option is provided a default sort order of 'ascending _id' is used (bson.M{"_id": 1})
+func (tc *TypedCollection[T]) Find(ctx context.Context, filter interface{}, opts ...FindOption) ([]T, int, error) {
+	results := make([]T, 0)
+
+	n, err := tc.collection.Find(ctx, filter, &results, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return results, n, nil
+}
+
+// FindOne returns a single document in the collection that satisfies the given filter (restricted by the
+// given options)
+// If no document could be found, an ErrNoDocumentFound error is returned
+func (tc *TypedCollection[T]) FindOne(ctx context.Context, filter interface{}, opts ...FindOption) (*T, error) {
+	var result T
+
+	if err := tc.collection.FindOne(ctx, filter, &result, opts...); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindOneAndUpdate returns a single document in the collection that satisfies the given filter (restricted by the
+// given options), and processes the update found in the update parameter
+// Whether the document returned is the one before or after the update is controlled by the ReturnDocument option
+// If no document could be found, an ErrNoDocumentFound error is returned
+func (tc *TypedCollection[T]) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...FindOption) (*T, error) {
+	var result T
+
+	if err := tc.collection.FindOneAndUpdate(ctx, filter, update, &result, opts...); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindCursor returns a typed cursor iterating over the collection
+// If no sort order option is provided a default sort order of 'ascending _id' is used (bson.M{"_id": 1})
+func (tc *TypedCollection[T]) FindCursor(ctx context.Context, filter interface{}, opts ...FindOption) (*TypedCursor[T], error) {
+	cursor, err := tc.collection.FindCursor(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedCursor[T]{cursor}, nil
+}
+
+// InsertOne creates a single document in the collection
+// If the document does not have an _id field when transformed into BSON, one will be added automatically to the marshalled document.
+// The _id can be retrieved from the InsertedId field of the returned CollectionInsertResult.
+func (tc *TypedCollection[T]) InsertOne(ctx context.Context, document T) (*CollectionInsertResult, error) {
+	return tc.collection.InsertOne(ctx, document)
+}
+
+// InsertMany creates multiple documents in the collection
+// The documents slice cannot be nil or empty.
+// For any document that does not have an _id field when transformed into BSON, one will be added automatically to the marshalled document.
+// The _id values for the inserted documents can be retrieved from the InsertedIds field of the returned CollectionInsertManyResult.
+func (tc *TypedCollection[T]) InsertMany(ctx context.Context, documents []T) (*CollectionInsertManyResult, error) {
+	docs := make([]interface{}, len(documents))
+	for i, d := range documents {
+		docs[i] = d
+	}
+
+	return tc.collection.InsertMany(ctx, docs)
+}
+
+// Next advances the cursor to the next document, returning false if there are no more documents or an error occurred
+func (c *TypedCursor[T]) Next(ctx context.Context) bool {
+	return c.cursor.Next(ctx)
+}
+
+// Decode returns the document the cursor is currently positioned at
+func (c *TypedCursor[T]) Decode() (*T, error) {
+	var result T
+
+	if err := c.cursor.Decode(&result); err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	return &result, nil
+}
+
+// All iterates over the remaining documents in the cursor, returning them as a slice, and closes the cursor
+func (c *TypedCursor[T]) All(ctx context.Context) ([]T, error) {
+	defer c.cursor.Close(ctx)
+
+	results := make([]T, 0)
+	for c.cursor.Next(ctx) {
+		var result T
+		if err := c.cursor.Decode(&result); err != nil {
+			return nil, wrapMongoError(err)
+		}
+		results = append(results, result)
+	}
+
+	if err := c.cursor.Err(); err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	return results, nil
+}
+
+// Err returns the last error seen by the cursor
+func (c *TypedCursor[T]) Err() error {
+	return wrapMongoError(c.cursor.Err())
+}
+
+// Close closes the cursor
+func (c *TypedCursor[T]) Close(ctx context.Context) error {
+	return wrapMongoError(c.cursor.Close(ctx))
+}
@@ -0,0 +1,138 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/dp-mongodb/v3/mongodb/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTypedCollection(t *testing.T) {
+	Convey("Given a mongo server cluster", t, func() {
+		ctx := context.Background()
+		conn, cleanup := setupMongoConnection(t)
+		defer cleanup(ctx)
+
+		Convey("and a typed collection setup with test data", func() {
+			if err := conn.DropDatabase(ctx); err != nil {
+				t.Fatalf("failed to drop database: %v", err)
+			}
+			tc := mongoDriver.NewTypedCollection[simpleObject](conn.Collection(collection1))
+			ir, err := tc.InsertMany(ctx, []simpleObject{{ID: 1, State: "first"}, {ID: 2, State: "second"}, {ID: 3, State: "first"}})
+			So(err, ShouldBeNil)
+			So(ir.InsertedIds, ShouldResemble, []interface{}{int32(1), int32(2), int32(3)})
+
+			Convey("Find returns the typed documents that satisfy the given filter, and the total count", func() {
+				res, n, err := tc.Find(ctx, bson.M{"state": "first"}, mongoDriver.Limit(1))
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 2)
+				So(res, ShouldResemble, []simpleObject{{ID: 1, State: "first"}})
+			})
+
+			Convey("Find returns an empty slice when no documents satisfy the given filter", func() {
+				res, n, err := tc.Find(ctx, bson.M{"state": "third"})
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 0)
+				So(res, ShouldResemble, []simpleObject{})
+			})
+
+			Convey("FindOne returns the typed document that satisfies the given filter", func() {
+				res, err := tc.FindOne(ctx, bson.M{"state": "second"})
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, &simpleObject{ID: 2, State: "second"})
+			})
+
+			Convey("FindOne returns an ErrNoDocumentFound error when no document satisfies the given filter", func() {
+				res, err := tc.FindOne(ctx, bson.M{"state": "third"})
+				So(err, ShouldEqual, mongoDriver.ErrNoDocumentFound)
+				So(res, ShouldBeNil)
+			})
+
+			Convey("FindOneAndUpdate returns the updated typed document", func() {
+				res, err := tc.FindOneAndUpdate(ctx, bson.M{"_id": 2}, bson.M{"$set": bson.M{"state": "third"}}, mongoDriver.ReturnDocument(options.After))
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, &simpleObject{ID: 2, State: "third"})
+			})
+
+			Convey("InsertOne inserts the typed document", func() {
+				ir, err := tc.InsertOne(ctx, simpleObject{ID: 4, State: "fourth"})
+				So(err, ShouldBeNil)
+				So(ir.InsertedId, ShouldEqual, 4)
+			})
+
+			Convey("FindCursor returns a typed cursor over the documents that satisfy the given filter", func() {
+				cursor, err := tc.FindCursor(ctx, bson.M{"state": "first"})
+				So(err, ShouldBeNil)
+				So(cursor.Next(ctx), ShouldBeTrue)
+				res, err := cursor.Decode()
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, &simpleObject{ID: 1, State: "first"})
+
+				rest, err := cursor.All(ctx)
+				So(err, ShouldBeNil)
+				So(rest, ShouldResemble, []simpleObject{{ID: 3, State: "first"}})
+			})
+		})
+	})
+}
+
+func TestTypedCollection_WithMock(t *testing.T) {
+	Convey("Given a typed collection wrapping a mocked collection", t, func() {
+		ctx := context.Background()
+		collection := &mock.CollectorMock{
+			FindOneFunc: func(ctx context.Context, filter interface{}, result interface{}, opts ...mongoDriver.FindOption) error {
+				return mongoDriver.ErrNoDocumentFound
+			},
+			FindFunc: func(ctx context.Context, filter interface{}, results interface{}, opts ...mongoDriver.FindOption) (int, error) {
+				*(results.(*[]simpleObject)) = []simpleObject{{ID: 1, State: "first"}}
+				return 3, nil
+			},
+		}
+		tc := mongoDriver.NewTypedCollection[simpleObject](collection)
+
+		Convey("FindOne returns the error returned by the collection", func() {
+			res, err := tc.FindOne(ctx, bson.M{"_id": 1})
+			So(err, ShouldEqual, mongoDriver.ErrNoDocumentFound)
+			So(res, ShouldBeNil)
+			So(collection.FindOneCalls(), ShouldHaveLength, 1)
+			So(collection.FindOneCalls()[0].Filter, ShouldResemble, bson.M{"_id": 1})
+		})
+
+		Convey("Find returns the documents and total count returned by the collection", func() {
+			res, n, err := tc.Find(ctx, bson.M{}, mongoDriver.Limit(1))
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 3)
+			So(res, ShouldResemble, []simpleObject{{ID: 1, State: "first"}})
+			So(collection.FindCalls()[0].Opts, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestMocks(t *testing.T) {
+	Convey("Given a mocked connection, returning a mocked collection, returning a mocked Must", t, func() {
+		ctx := context.Background()
+		must := &mock.MustAPIMock{
+			UpdateOneFunc: func(ctx context.Context, selector interface{}, update interface{}) (*mongoDriver.CollectionUpdateResult, error) {
+				return nil, mongoDriver.ErrNoDocumentFound
+			},
+		}
+		collection := &mock.CollectorMock{
+			MustAPIFunc: func() mongoDriver.MustAPI { return must },
+		}
+		var conn mongoDriver.MongoConnector = &mock.MongoConnectorMock{
+			CollectorFunc: func(name string) mongoDriver.Collector { return collection },
+		}
+
+		Convey("The mocks are called through the MongoConnector, Collector and MustAPI interfaces", func() {
+			_, err := conn.Collector("datasets").MustAPI().UpdateOne(ctx, bson.M{"_id": 1}, bson.M{"$set": bson.M{"state": "published"}})
+			So(err, ShouldEqual, mongoDriver.ErrNoDocumentFound)
+			So(must.UpdateOneCalls(), ShouldHaveLength, 1)
+			So(collection.MustAPICalls(), ShouldHaveLength, 1)
+		})
+	})
+}
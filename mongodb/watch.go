@@ -0,0 +1,236 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OperationType is the type of operation that caused a change event
+type OperationType string
+
+const (
+	OperationInsert     OperationType = "insert"
+	OperationUpdate     OperationType = "update"
+	OperationReplace    OperationType = "replace"
+	OperationDelete     OperationType = "delete"
+	OperationDrop       OperationType = "drop"
+	OperationRename     OperationType = "rename"
+	OperationInvalidate OperationType = "invalidate"
+)
+
+// ErrNoResumeTokenStore is returned when a change stream is checkpointed without a ResumeTokenStore having been given
+var ErrNoResumeTokenStore = errors.New("no resume token store configured for change stream")
+
+// ResumeTokenStore persists the resume token of a change stream consumer, so that the consumer can restart the
+// change stream from where it stopped
+type ResumeTokenStore interface {
+	// LoadResumeToken returns the last saved resume token for the consumer, or nil if no token has been saved
+	LoadResumeToken(ctx context.Context, consumer string) (bson.Raw, error)
+	// SaveResumeToken saves the resume token for the consumer, replacing any previously saved token
+	SaveResumeToken(ctx context.Context, consumer string, token bson.Raw) error
+}
+
+// ChangeNamespace identifies the database and collection in which a change occurred
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll"`
+}
+
+// UpdateDescription describes the fields changed by an update operation
+type UpdateDescription struct {
+	UpdatedFields bson.Raw `bson:"updatedFields"`
+	RemovedFields []string `bson:"removedFields"`
+}
+
+// ChangeEvent is a single event returned from a change stream
+type ChangeEvent struct {
+	ResumeToken       bson.Raw            `bson:"_id"`
+	OperationType     OperationType       `bson:"operationType"`
+	Namespace         ChangeNamespace     `bson:"ns"`
+	DocumentKey       bson.Raw            `bson:"documentKey,omitempty"`
+	FullDocument      bson.Raw            `bson:"fullDocument,omitempty"`
+	UpdateDescription *UpdateDescription  `bson:"updateDescription,omitempty"`
+	ClusterTime       primitive.Timestamp `bson:"clusterTime"`
+}
+
+// DecodeFullDocument decodes the full document of the event into the given value (which must be a non nil pointer
+// to a document of the expected type)
+// If the event carries no full document, an ErrNoDocumentFound error is returned
+func (e *ChangeEvent) DecodeFullDocument(val interface{}) error {
+	if len(e.FullDocument) == 0 {
+		return ErrNoDocumentFound
+	}
+
+	return wrapMongoError(bson.Unmarshal(e.FullDocument, val))
+}
+
+// ChangeStream is a stream of change events from a collection or database
+type ChangeStream struct {
+	stream   *mongo.ChangeStream
+	store    ResumeTokenStore
+	consumer string
+}
+
+type WatchOption func(*watchOptions)
+
+var (
+	FullDocument = func(fd options.FullDocument) WatchOption { return func(w *watchOptions) { w.fullDocument = fd } }
+	ResumeAfter  = func(token bson.Raw) WatchOption { return func(w *watchOptions) { w.resumeAfter = token } }
+	StartAfter   = func(token bson.Raw) WatchOption { return func(w *watchOptions) { w.startAfter = token } }
+	BatchSize    = func(s int) WatchOption { return func(w *watchOptions) { w.batchSize = int32(s) } }
+	MaxAwaitTime = func(d time.Duration) WatchOption { return func(w *watchOptions) { w.maxAwaitTime = d } }
+
+	// WithResumeTokenStore resumes the change stream from the token saved in the store for the given consumer (if
+	// any), and allows ChangeStream.Checkpoint to save the stream's position to the store
+	WithResumeTokenStore = func(store ResumeTokenStore, consumer string) WatchOption {
+		return func(w *watchOptions) { w.store, w.consumer = store, consumer }
+	}
+)
+
+type watchOptions struct {
+	fullDocument options.FullDocument
+	resumeAfter  bson.Raw
+	startAfter   bson.Raw
+	batchSize    int32
+	maxAwaitTime time.Duration
+	store        ResumeTokenStore
+	consumer     string
+}
+
+func newWatchOptions(opts ...WatchOption) *watchOptions {
+	w := &watchOptions{}
+	for _, o := range opts {
+		o(w)
+	}
+
+	return w
+}
+
+// asDriverChangeStreamOption returns the driver options for the change stream, loading the resume token from the
+// resume token store if one was given. A token loaded from the store takes precedence over a ResumeAfter option
+func (wo watchOptions) asDriverChangeStreamOption(ctx context.Context) (*options.ChangeStreamOptions, error) {
+	co := options.ChangeStream()
+	if wo.fullDocument != "" {
+		co.SetFullDocument(wo.fullDocument)
+	}
+	if wo.batchSize > 0 {
+		co.SetBatchSize(wo.batchSize)
+	}
+	if wo.maxAwaitTime > 0 {
+		co.SetMaxAwaitTime(wo.maxAwaitTime)
+	}
+
+	resumeAfter := wo.resumeAfter
+	if wo.store != nil {
+		token, err := wo.store.LoadResumeToken(ctx, wo.consumer)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			resumeAfter = token
+		}
+	}
+
+	switch {
+	case resumeAfter != nil:
+		co.SetResumeAfter(resumeAfter)
+	case wo.startAfter != nil:
+		co.SetStartAfter(wo.startAfter)
+	}
+
+	return co, nil
+}
+
+type watcher interface {
+	Watch(ctx context.Context, pipeline interface{}, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error)
+}
+
+func watch(ctx context.Context, w watcher, pipeline interface{}, opts ...WatchOption) (*ChangeStream, error) {
+	wo := newWatchOptions(opts...)
+	co, err := wo.asDriverChangeStreamOption(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+	stream, err := w.Watch(ctx, pipeline, co)
+	if err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	return &ChangeStream{stream: stream, store: wo.store, consumer: wo.consumer}, nil
+}
+
+// Watch returns a change stream for all changes to the collection, filtered by the given (optional) aggregation
+// pipeline. The query timeout does not apply to a change stream, which is bounded only by the contexts passed to it
+func (c *Collection) Watch(ctx context.Context, pipeline interface{}, opts ...WatchOption) (_ *ChangeStream, err error) {
+	ctx, op := c.startOperation(ctx, "Watch")
+	defer op.end(&err)
+	op.filter(pipeline)
+
+	return watch(ctx, c.collection, pipeline, opts...)
+}
+
+// Watch returns a change stream for all changes to the configured database, filtered by the given (optional)
+// aggregation pipeline
+func (ms *MongoConnection) Watch(ctx context.Context, pipeline interface{}, opts ...WatchOption) (*ChangeStream, error) {
+	return watch(ctx, ms.d(), pipeline, opts...)
+}
+
+// Next blocks until the next event is available, returning false if the stream has been closed or an error occurred
+func (cs *ChangeStream) Next(ctx context.Context) bool {
+	return cs.stream.Next(ctx)
+}
+
+// TryNext returns true if an event is available, without blocking for new events from the server
+func (cs *ChangeStream) TryNext(ctx context.Context) bool {
+	return cs.stream.TryNext(ctx)
+}
+
+// Event returns the event the stream is currently positioned at
+func (cs *ChangeStream) Event() (*ChangeEvent, error) {
+	e := &ChangeEvent{}
+	if err := cs.stream.Decode(e); err != nil {
+		return nil, wrapMongoError(err)
+	}
+
+	return e, nil
+}
+
+// ResumeToken returns the token identifying the stream's current position
+func (cs *ChangeStream) ResumeToken() bson.Raw {
+	return cs.stream.ResumeToken()
+}
+
+// Checkpoint saves the stream's current position to the resume token store, so that a restarted consumer resumes
+// after the last event processed. It should be called once an event has been fully processed
+func (cs *ChangeStream) Checkpoint(ctx context.Context) error {
+	if cs.store == nil {
+		return ErrNoResumeTokenStore
+	}
+
+	token := cs.stream.ResumeToken()
+	if token == nil {
+		return nil
+	}
+
+	return cs.store.SaveResumeToken(ctx, cs.consumer, token)
+}
+
+// Err returns the last error seen by the stream
+func (cs *ChangeStream) Err() error {
+	return wrapMongoError(cs.stream.Err())
+}
+
+// Close closes the stream
+func (cs *ChangeStream) Close(ctx context.Context) error {
+	return wrapMongoError(cs.stream.Close(ctx))
+}
@@ -0,0 +1,142 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type memoryResumeTokenStore map[string]bson.Raw
+
+func (s memoryResumeTokenStore) LoadResumeToken(_ context.Context, consumer string) (bson.Raw, error) {
+	return s[consumer], nil
+}
+
+func (s memoryResumeTokenStore) SaveResumeToken(_ context.Context, consumer string, token bson.Raw) error {
+	s[consumer] = token
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	Convey("Given a mongo server cluster", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		conn, cleanup := setupMongoConnection(t)
+		defer cleanup(ctx)
+
+		if err := conn.DropDatabase(ctx); err != nil {
+			t.Fatalf("failed to drop database: %v", err)
+		}
+		collection := conn.Collection(collection1)
+
+		Convey("When a change stream is opened on a collection with a resume token store", func() {
+			store := memoryResumeTokenStore{}
+			cs, err := collection.Watch(ctx, nil, mongoDriver.FullDocument(options.UpdateLookup), mongoDriver.WithResumeTokenStore(store, "consumer"))
+			So(err, ShouldBeNil)
+			defer cs.Close(ctx)
+
+			_, err = collection.InsertOne(ctx, simpleObject{ID: 1, State: "first"})
+			So(err, ShouldBeNil)
+			_, err = collection.UpdateOne(ctx, bson.M{"_id": 1}, bson.M{"$set": bson.M{"state": "second"}})
+			So(err, ShouldBeNil)
+			_, err = collection.DeleteOne(ctx, bson.M{"_id": 1})
+			So(err, ShouldBeNil)
+
+			Convey("Then the change events are returned in order, with the full document", func() {
+				So(cs.Next(ctx), ShouldBeTrue)
+				e, err := cs.Event()
+				So(err, ShouldBeNil)
+				So(e.OperationType, ShouldEqual, mongoDriver.OperationInsert)
+				So(e.Namespace, ShouldResemble, mongoDriver.ChangeNamespace{Database: db, Collection: collection1})
+				var obj simpleObject
+				So(e.DecodeFullDocument(&obj), ShouldBeNil)
+				So(obj, ShouldResemble, simpleObject{ID: 1, State: "first"})
+
+				So(cs.Next(ctx), ShouldBeTrue)
+				e, err = cs.Event()
+				So(err, ShouldBeNil)
+				So(e.OperationType, ShouldEqual, mongoDriver.OperationUpdate)
+				So(e.UpdateDescription, ShouldNotBeNil)
+				So(e.UpdateDescription.UpdatedFields.Lookup("state").StringValue(), ShouldEqual, "second")
+
+				Convey("And a stream reopened after a checkpoint resumes after the checkpointed event", func() {
+					So(cs.Checkpoint(ctx), ShouldBeNil)
+					So(store["consumer"], ShouldNotBeNil)
+					So(cs.Close(ctx), ShouldBeNil)
+
+					resumed, err := collection.Watch(ctx, nil, mongoDriver.WithResumeTokenStore(store, "consumer"))
+					So(err, ShouldBeNil)
+					defer resumed.Close(ctx)
+
+					So(resumed.Next(ctx), ShouldBeTrue)
+					e, err = resumed.Event()
+					So(err, ShouldBeNil)
+					So(e.OperationType, ShouldEqual, mongoDriver.OperationDelete)
+					So(e.FullDocument, ShouldBeNil)
+					So(e.DecodeFullDocument(&obj), ShouldEqual, mongoDriver.ErrNoDocumentFound)
+				})
+			})
+		})
+
+		Convey("When a collection resume token store is used", func() {
+			store := mongoDriver.NewCollectionResumeTokenStore(conn.Collection(mongoDriver.DefaultResumeTokenCollection))
+
+			Convey("Then no token is loaded for a consumer that has not saved one", func() {
+				token, err := store.LoadResumeToken(ctx, "consumer")
+				So(err, ShouldBeNil)
+				So(token, ShouldBeNil)
+			})
+
+			Convey("Then the latest token saved for a consumer is loaded", func() {
+				cs, err := collection.Watch(ctx, nil, mongoDriver.WithResumeTokenStore(store, "consumer"))
+				So(err, ShouldBeNil)
+				defer cs.Close(ctx)
+
+				_, err = collection.InsertOne(ctx, simpleObject{ID: 1, State: "first"})
+				So(err, ShouldBeNil)
+				_, err = collection.InsertOne(ctx, simpleObject{ID: 2, State: "second"})
+				So(err, ShouldBeNil)
+
+				So(cs.Next(ctx), ShouldBeTrue)
+				So(cs.Checkpoint(ctx), ShouldBeNil)
+				So(cs.Next(ctx), ShouldBeTrue)
+				So(cs.Checkpoint(ctx), ShouldBeNil)
+
+				token, err := store.LoadResumeToken(ctx, "consumer")
+				So(err, ShouldBeNil)
+				So(token, ShouldResemble, cs.ResumeToken())
+
+				other, err := store.LoadResumeToken(ctx, "other-consumer")
+				So(err, ShouldBeNil)
+				So(other, ShouldBeNil)
+			})
+		})
+
+		Convey("When a change stream is opened on the database, without a resume token store", func() {
+			cs, err := conn.Watch(ctx, bson.A{bson.M{"$match": bson.M{"operationType": "insert"}}})
+			So(err, ShouldBeNil)
+			defer cs.Close(ctx)
+
+			_, err = conn.Collection(collection2).InsertOne(ctx, simpleObject{ID: 2, State: "first"})
+			So(err, ShouldBeNil)
+
+			Convey("Then the change events for all collections are returned", func() {
+				So(cs.Next(ctx), ShouldBeTrue)
+				e, err := cs.Event()
+				So(err, ShouldBeNil)
+				So(e.OperationType, ShouldEqual, mongoDriver.OperationInsert)
+				So(e.Namespace.Collection, ShouldEqual, collection2)
+			})
+
+			Convey("Then the stream cannot be checkpointed", func() {
+				So(cs.Checkpoint(ctx), ShouldEqual, mongoDriver.ErrNoResumeTokenStore)
+			})
+		})
+	})
+}
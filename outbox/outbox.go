@@ -0,0 +1,88 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection is the name of the collection in which messages are enqueued
+const Collection = "outbox"
+
+// State is the state of a message in the outbox
+type State string
+
+const (
+	Pending    State = "pending"     // The message has not yet been published
+	Sent       State = "sent"        // The message has been published
+	DeadLetter State = "dead_letter" // The message could not be published within the maximum number of attempts
+)
+
+// Message is a message to be published, as stored in the outbox
+type Message struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Topic         string             `bson:"topic"`
+	Key           string             `bson:"key,omitempty"`
+	Payload       []byte             `bson:"payload"`
+	Headers       map[string]string  `bson:"headers,omitempty"`
+	State         State              `bson:"state"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty"`
+}
+
+// Indexes are the indexes of the outbox collection used by the relay to find the pending messages in order
+var Indexes = []mongoDriver.IndexSpec{
+	{Name: "state_1__id_1", Keys: bson.D{{Key: "state", Value: 1}, {Key: "_id", Value: 1}}},
+}
+
+// Outbox enqueues messages in the outbox collection, within the transaction of the business writes they describe, to
+// be published by a Relay once the transaction has committed
+type Outbox struct {
+	Collection mongoDriver.CollectionAPI
+}
+
+// New creates a new outbox for the provided connection
+func New(mongoConnection *mongoDriver.MongoConnection) *Outbox {
+	return &Outbox{Collection: mongoConnection.Collection(Collection)}
+}
+
+// Enqueue stores the messages in the outbox, in order, as pending messages to be published. It must be called with
+// the transaction context given to a TransactionFunc by RunTransaction (as reported by mongodb.InTransaction), so that
+// the messages are only stored, and so published, if the transaction commits; otherwise mongodb.ErrNoTransaction is
+// returned
+// Only the Topic, Key, Payload and Headers of the messages are used
+func (o *Outbox) Enqueue(transactionCtx context.Context, messages ...Message) error {
+	if !mongoDriver.InTransaction(transactionCtx) {
+		return mongoDriver.ErrNoTransaction
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return o.enqueue(transactionCtx, time.Now().UTC(), messages)
+}
+
+func (o *Outbox) enqueue(ctx context.Context, now time.Time, messages []Message) error {
+	documents := make([]interface{}, len(messages))
+	for i, m := range messages {
+		documents[i] = Message{
+			ID:            primitive.NewObjectID(),
+			Topic:         m.Topic,
+			Key:           m.Key,
+			Payload:       m.Payload,
+			Headers:       m.Headers,
+			State:         Pending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+	}
+
+	_, err := o.Collection.InsertMany(ctx, documents)
+	return err
+}
@@ -0,0 +1,92 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/dp-mongodb/v3/mongodb/mongotest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOutbox_Enqueue(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	Convey("Given an outbox", t, func() {
+		o := &Outbox{Collection: mongotest.NewCollection()}
+
+		Convey("When messages are enqueued outside a transaction", func() {
+			err := o.Enqueue(ctx, Message{Topic: "dataset-published"})
+
+			Convey("Then ErrNoTransaction is returned, and nothing is stored", func() {
+				So(err, ShouldEqual, mongoDriver.ErrNoTransaction)
+				count, err := o.Collection.Count(ctx, bson.D{})
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When messages are enqueued with the context of a session started directly with the driver", func() {
+			session, err := client.StartSession()
+			So(err, ShouldBeNil)
+			defer session.EndSession(ctx)
+			So(session.StartTransaction(), ShouldBeNil)
+			err = o.Enqueue(mongo.NewSessionContext(ctx, session), Message{Topic: "dataset-published"})
+
+			Convey("Then ErrNoTransaction is returned, since it is not a transaction context given by RunTransaction", func() {
+				So(err, ShouldEqual, mongoDriver.ErrNoTransaction)
+			})
+		})
+
+		Convey("When messages are enqueued within RunTransaction", func() {
+			conn := mongoDriver.NewMongoConnection(client, "test-db")
+			_, err := conn.RunTransaction(ctx, false, func(transactionCtx context.Context) (interface{}, error) {
+				return nil, o.Enqueue(transactionCtx, Message{Topic: "dataset-published"}, Message{Topic: "dataset-updated"})
+			})
+
+			Convey("Then the messages are stored", func() {
+				So(err, ShouldBeNil)
+				count, err := o.Collection.Count(ctx, bson.M{"state": Pending})
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When messages are stored", func() {
+			now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			err := o.enqueue(ctx, now, []Message{
+				{Topic: "dataset-published", Key: "cpih", Payload: []byte("first"), Headers: map[string]string{"trace": "1"}},
+				{Topic: "dataset-published", Key: "cpih", Payload: []byte("second"), State: Sent, Attempts: 3},
+			})
+
+			Convey("Then they are stored in order as pending messages, ready to be published", func() {
+				So(err, ShouldBeNil)
+
+				var messages []Message
+				_, err = o.Collection.Find(ctx, bson.D{}, &messages)
+				So(err, ShouldBeNil)
+				So(messages, ShouldHaveLength, 2)
+				So(string(messages[0].Payload), ShouldEqual, "first")
+				So(messages[0].Headers, ShouldResemble, map[string]string{"trace": "1"})
+				So(string(messages[1].Payload), ShouldEqual, "second")
+				for _, m := range messages {
+					So(m.State, ShouldEqual, Pending)
+					So(m.Attempts, ShouldEqual, 0)
+					So(m.CreatedAt, ShouldEqual, now)
+					So(m.NextAttemptAt, ShouldEqual, now)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,251 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ONSdigital/dp-mongodb/v3/dplock"
+	mongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"github.com/ONSdigital/log.go/v2/log"
+	lock "github.com/square/mongo-lock"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// lockResourceID is the id of the resource locked while messages are relayed
+const lockResourceID = "relay"
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 10
+	defaultBackoff      = time.Second
+	defaultMaxBackoff   = 5 * time.Minute
+
+	// lockLease is the time from taking the relay lock during which messages are published, leaving a margin before
+	// the lock expires after dplock.TTL seconds in which to mark the last message published as sent, so that another
+	// instance cannot take the lock and publish the same messages
+	lockLease = (dplock.TTL - 10) * time.Second
+)
+
+// Publisher publishes the messages relayed from the outbox, e.g. to a Kafka topic
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// Locker defines the dplock.Lock methods used to ensure only one instance relays messages at a time
+type Locker interface {
+	Lock(ctx context.Context, resourceID string) (lockID string, err error)
+	Unlock(ctx context.Context, lockID string)
+	Close(ctx context.Context)
+}
+
+type RelayOption func(*relayOptions)
+
+var (
+	// PollInterval is the time between checks for pending messages (default 1s)
+	PollInterval = func(d time.Duration) RelayOption { return func(r *relayOptions) { r.pollInterval = d } }
+	// BatchSize is the maximum number of messages relayed in each pass (default 100)
+	BatchSize = func(n int) RelayOption { return func(r *relayOptions) { r.batchSize = n } }
+	// MaxAttempts is the number of attempts to publish a message before it is dead-lettered (default 10)
+	MaxAttempts = func(n int) RelayOption { return func(r *relayOptions) { r.maxAttempts = n } }
+	// RetryBackoff is the backoff after the first failed attempt to publish a message (default 1s), doubled for each
+	// further attempt up to the maximum backoff (default 5m)
+	RetryBackoff = func(backoff, maxBackoff time.Duration) RelayOption {
+		return func(r *relayOptions) { r.backoff, r.maxBackoff = backoff, maxBackoff }
+	}
+)
+
+type relayOptions struct {
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+}
+
+func newRelayOptions(opts ...RelayOption) *relayOptions {
+	r := &relayOptions{
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		maxAttempts:  defaultMaxAttempts,
+		backoff:      defaultBackoff,
+		maxBackoff:   defaultMaxBackoff,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// delay returns the backoff after the given number of failed attempts: the backoff doubled for each attempt after the
+// first, up to the maximum backoff, of which a random amount of up to half is taken off
+func (ro relayOptions) delay(attempts int) time.Duration {
+	d := ro.backoff
+	for i := 1; i < attempts && d < ro.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > ro.maxBackoff {
+		d = ro.maxBackoff
+	}
+
+	return d - rand.N(d/2+1)
+}
+
+// Relay publishes the pending messages of the outbox, in order, with a Publisher, and marks them as sent
+// A message is published at least once: if it cannot be marked as sent after it is published, it is published again
+// A message that fails to be published is retried after a backoff, and messages after it are not published until it
+// has been, so that messages are published in order. After the maximum number of attempts, the message is
+// dead-lettered, and the messages after it are published
+type Relay struct {
+	Collection mongoDriver.CollectionAPI
+	Lock       Locker
+	Publisher  Publisher
+	options    *relayOptions
+	now        func() time.Time
+}
+
+// NewRelay creates a new relay of the outbox of the provided connection, with a dplock.Lock to coordinate instances,
+// and creates the indexes of the outbox collection
+func NewRelay(ctx context.Context, mongoConnection *mongoDriver.MongoConnection, publisher Publisher, opts ...RelayOption) (*Relay, error) {
+	collection := mongoConnection.Collection(Collection)
+	if _, err := collection.EnsureIndexes(ctx, Indexes); err != nil {
+		return nil, err
+	}
+
+	return &Relay{
+		Collection: collection,
+		Lock:       dplock.New(ctx, mongoConnection, Collection),
+		Publisher:  publisher,
+		options:    newRelayOptions(opts...),
+		now:        time.Now,
+	}, nil
+}
+
+// Run relays the pending messages every poll interval, until the context is done
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts().pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Error(ctx, "failed to relay outbox messages", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending makes a single pass of the relay, under the relay lock: it publishes up to a batch of the pending
+// messages, in order, stopping at the first message that fails to be published or is waiting to be retried
+// It returns the number of messages published, or 0 if another instance holds the relay lock
+// The lock is not renewed, so the pass also stops, leaving the rest of the batch to the next pass, when the lock's
+// lease is used up, and each message is published with a context that is done when the lease ends
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	lockedAt := r.clock()
+	lockID, err := r.Lock.Lock(ctx, lockResourceID)
+	if errors.Is(err, lock.ErrAlreadyLocked) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer r.Lock.Unlock(ctx, lockID)
+
+	ro := r.opts()
+
+	var messages []Message
+	_, err = r.Collection.Find(ctx, bson.M{"state": Pending}, &messages,
+		mongoDriver.Sort(bson.D{{Key: "_id", Value: 1}}), mongoDriver.Limit(ro.batchSize), mongoDriver.WithoutTotalCount())
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, m := range messages {
+		now := r.clock()
+		if m.NextAttemptAt.After(now) {
+			break
+		}
+
+		lease := lockLease - now.Sub(lockedAt)
+		if lease <= 0 {
+			log.Warn(ctx, "outbox relay lock lease used up, leaving the remaining messages to the next pass", log.Data{
+				"published": published,
+				"remaining": len(messages) - published,
+			})
+			break
+		}
+
+		publishCtx, cancel := context.WithTimeout(ctx, lease)
+		err = r.Publisher.Publish(publishCtx, m)
+		cancel()
+		if err != nil {
+			if deadLettered, ferr := r.failed(ctx, m, err, now); ferr != nil || !deadLettered {
+				return published, ferr
+			}
+			continue
+		}
+
+		if _, err = r.Collection.UpdateOne(ctx, bson.M{"_id": m.ID, "state": Pending}, bson.M{
+			"$set": bson.M{"state": Sent, "sent_at": now},
+			"$inc": bson.M{"attempts": 1},
+		}); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// failed records a failed attempt to publish the message, scheduling its next attempt after a backoff, or
+// dead-lettering it if it has reached the maximum number of attempts. It returns true if the message was dead-lettered
+func (r *Relay) failed(ctx context.Context, m Message, publishErr error, now time.Time) (bool, error) {
+	ro := r.opts()
+	attempts := m.Attempts + 1
+	logData := log.Data{"message_id": m.ID.Hex(), "topic": m.Topic, "attempts": attempts}
+
+	set := bson.M{"last_error": publishErr.Error()}
+	deadLettered := attempts >= ro.maxAttempts
+	if deadLettered {
+		set["state"] = DeadLetter
+		log.Error(ctx, "outbox message dead-lettered", publishErr, logData)
+	} else {
+		delay := ro.delay(attempts)
+		set["next_attempt_at"] = now.Add(delay)
+		logData["backoff"] = delay.String()
+		logData["error"] = publishErr.Error()
+		log.Warn(ctx, "failed to publish outbox message", logData)
+	}
+
+	_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": m.ID, "state": Pending}, bson.M{"$set": set, "$inc": bson.M{"attempts": 1}})
+	return deadLettered, err
+}
+
+// Close closes the relay lock
+func (r *Relay) Close(ctx context.Context) {
+	r.Lock.Close(ctx)
+}
+
+func (r *Relay) opts() *relayOptions {
+	if r.options == nil {
+		r.options = newRelayOptions()
+	}
+
+	return r.options
+}
+
+func (r *Relay) clock() time.Time {
+	if r.now == nil {
+		return time.Now().UTC()
+	}
+
+	return r.now().UTC()
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-mongodb/v3/mongodb/mongotest"
+	lock "github.com/square/mongo-lock"
+	"go.mongodb.org/mongo-driver/bson"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var errPublish = errors.New("broker unavailable")
+
+// testPublisher records the payloads of the messages published, and fails for the payloads in failing
+type testPublisher struct {
+	published []string
+	failing   map[string]bool
+	onPublish func(ctx context.Context)
+}
+
+func (p *testPublisher) Publish(ctx context.Context, m Message) error {
+	if p.onPublish != nil {
+		p.onPublish(ctx)
+	}
+	if p.failing[string(m.Payload)] {
+		return errPublish
+	}
+	p.published = append(p.published, string(m.Payload))
+	return nil
+}
+
+// testLocker is a Locker that records the locks taken and released, and can be held by another instance
+type testLocker struct {
+	locked, unlocked int
+	heldElsewhere    bool
+}
+
+func (l *testLocker) Lock(context.Context, string) (string, error) {
+	if l.heldElsewhere {
+		return "", lock.ErrAlreadyLocked
+	}
+	l.locked++
+	return "lock", nil
+}
+
+func (l *testLocker) Unlock(context.Context, string) {
+	l.unlocked++
+}
+
+func (l *testLocker) Close(context.Context) {}
+
+func messagesByState(ctx context.Context, r *Relay) map[State][]string {
+	var messages []Message
+	_, err := r.Collection.Find(ctx, bson.D{}, &messages)
+	So(err, ShouldBeNil)
+
+	states := map[State][]string{}
+	for _, m := range messages {
+		states[m.State] = append(states[m.State], string(m.Payload))
+	}
+
+	return states
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a relay of an outbox with pending messages", t, func() {
+		now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		publisher := &testPublisher{failing: map[string]bool{}}
+		locker := &testLocker{}
+		o := &Outbox{Collection: mongotest.NewCollection()}
+		r := &Relay{
+			Collection: o.Collection,
+			Lock:       locker,
+			Publisher:  publisher,
+			options:    newRelayOptions(MaxAttempts(2), RetryBackoff(time.Minute, time.Hour)),
+			now:        func() time.Time { return now },
+		}
+
+		So(o.enqueue(ctx, now, []Message{{Payload: []byte("1")}, {Payload: []byte("2")}, {Payload: []byte("3")}}), ShouldBeNil)
+
+		Convey("When the messages are relayed", func() {
+			n, err := r.RelayPending(ctx)
+
+			Convey("Then they are published in order and marked as sent, under the relay lock", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 3)
+				So(publisher.published, ShouldResemble, []string{"1", "2", "3"})
+				So(messagesByState(ctx, r), ShouldResemble, map[State][]string{Sent: {"1", "2", "3"}})
+				So(locker.locked, ShouldEqual, 1)
+				So(locker.unlocked, ShouldEqual, 1)
+			})
+
+			Convey("Then they are not published again", func() {
+				n, err = r.RelayPending(ctx)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 0)
+				So(publisher.published, ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("When the messages are relayed in batches", func() {
+			r.options.batchSize = 2
+			n, err := r.RelayPending(ctx)
+
+			Convey("Then only a batch is published in each pass", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 2)
+				So(publisher.published, ShouldResemble, []string{"1", "2"})
+			})
+		})
+
+		Convey("When the publisher is slow, taking 8s of the lock's lease of 20s to publish each message", func() {
+			var timeouts []time.Duration
+			publisher.onPublish = func(ctx context.Context) {
+				deadline, _ := ctx.Deadline()
+				timeouts = append(timeouts, time.Until(deadline).Round(time.Second))
+				now = now.Add(8 * time.Second)
+			}
+			So(o.enqueue(ctx, now, []Message{{Payload: []byte("4")}}), ShouldBeNil)
+			n, err := r.RelayPending(ctx)
+
+			Convey("Then the pass stops once the lease is used up, before the lock can expire", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 3)
+				So(publisher.published, ShouldResemble, []string{"1", "2", "3"})
+				So(messagesByState(ctx, r), ShouldResemble, map[State][]string{Sent: {"1", "2", "3"}, Pending: {"4"}})
+				So(locker.unlocked, ShouldEqual, 1)
+			})
+
+			Convey("Then each message is published with a context that is done when the lease ends", func() {
+				So(timeouts, ShouldResemble, []time.Duration{20 * time.Second, 12 * time.Second, 4 * time.Second})
+			})
+
+			Convey("Then the remaining messages are published in the next pass", func() {
+				n, err = r.RelayPending(ctx)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 1)
+				So(publisher.published, ShouldResemble, []string{"1", "2", "3", "4"})
+			})
+		})
+
+		Convey("When another instance holds the relay lock", func() {
+			locker.heldElsewhere = true
+			n, err := r.RelayPending(ctx)
+
+			Convey("Then no messages are published", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 0)
+				So(publisher.published, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a message fails to be published", func() {
+			publisher.failing["2"] = true
+			n, err := r.RelayPending(ctx)
+
+			Convey("Then the messages after it are not published, and it is scheduled to be retried after a backoff", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 1)
+				So(publisher.published, ShouldResemble, []string{"1"})
+
+				var m Message
+				So(r.Collection.FindOne(ctx, bson.M{"payload": []byte("2")}, &m), ShouldBeNil)
+				So(m.State, ShouldEqual, Pending)
+				So(m.Attempts, ShouldEqual, 1)
+				So(m.LastError, ShouldEqual, errPublish.Error())
+				So(m.NextAttemptAt, ShouldHappenOnOrBetween, now.Add(30*time.Second), now.Add(time.Minute))
+			})
+
+			Convey("Then it is not retried before its backoff has passed", func() {
+				publisher.failing["2"] = false
+				n, err = r.RelayPending(ctx)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 0)
+			})
+
+			Convey("Then it and the messages after it are published once its backoff has passed", func() {
+				publisher.failing["2"] = false
+				now = now.Add(time.Minute)
+				n, err = r.RelayPending(ctx)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 2)
+				So(publisher.published, ShouldResemble, []string{"1", "2", "3"})
+			})
+
+			Convey("Then it is dead-lettered when it fails the maximum number of attempts, and the messages after it are published", func() {
+				now = now.Add(time.Minute)
+				n, err = r.RelayPending(ctx)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, 1)
+				So(publisher.published, ShouldResemble, []string{"1", "3"})
+				So(messagesByState(ctx, r), ShouldResemble, map[State][]string{Sent: {"1", "3"}, DeadLetter: {"2"}})
+			})
+		})
+	})
+}
+
+func TestRelayOptionsDelay(t *testing.T) {
+	Convey("Given relay options with a backoff", t, func() {
+		ro := newRelayOptions(RetryBackoff(time.Second, 10*time.Second))
+
+		Convey("Then the backoff doubles for each failed attempt, with up to half taken off by jitter, up to the maximum", func() {
+			for i := 0; i < 20; i++ {
+				So(ro.delay(1), ShouldBeBetweenOrEqual, 500*time.Millisecond, time.Second)
+				So(ro.delay(3), ShouldBeBetweenOrEqual, 2*time.Second, 4*time.Second)
+				So(ro.delay(20), ShouldBeBetweenOrEqual, 5*time.Second, 10*time.Second)
+			}
+		})
+	})
+}